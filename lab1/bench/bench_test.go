@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testSource Исходный файл со случайным содержимым во временном каталоге.
+func testSource(t *testing.T, size int64) SourceFile {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "src.bin")
+	if err := createDummyFile(path, size, PatternRandom, 1); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := fileChecksum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return SourceFile{Path: path, Size: size, Checksum: sum}
+}
+
+func TestVerifyCopy(t *testing.T) {
+	src := testSource(t, 64*KB)
+	data, err := os.ReadFile(src.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(corrupted)/2] ^= 0xff
+
+	tests := []struct {
+		name    string
+		content []byte // nil — приемника нет
+		wantErr string
+	}{
+		{"identical", data, ""},
+		{"missing", nil, "destination missing"},
+		{"empty", []byte{}, "size mismatch"},
+		{"short", data[:len(data)-1], "size mismatch"},
+		{"long", append(append([]byte(nil), data...), 0), "size mismatch"},
+		{"corrupted", corrupted, "checksum mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "dst.bin")
+			if tt.content != nil {
+				if err := os.WriteFile(dst, tt.content, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := verifyCopy(dst, src.Size, src.Checksum)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("verifyCopy: %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("verifyCopy: %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifySample(t *testing.T) {
+	const n = 1000
+	pick := func(seed int64) []bool {
+		s := newVerifySample(seed)
+		picked := make([]bool, n)
+		for i := range picked {
+			picked[i] = s.Check(i)
+		}
+		return picked
+	}
+
+	a, b, other := pick(1), pick(1), pick(2)
+	if !a[0] || !other[0] {
+		t.Error("first iteration is not checked")
+	}
+	count, differs := 0, false
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			t.Fatalf("iteration %d: sample differs for the same seed", i)
+		}
+		if a[i] {
+			count++
+		}
+		differs = differs || a[i] != other[i]
+	}
+	if !differs {
+		t.Error("different seeds give the same sample")
+	}
+	// Примерно VerifySampleRate от всех итераций
+	if want := int(VerifySampleRate * n); count < want/2 || count > want*2 {
+		t.Errorf("%d of %d iterations checked, want about %d", count, n, want)
+	}
+}
+
+// shSubject Программа-скрипт: $0 — исходник, $1 — приемник.
+func shSubject(t *testing.T, script string) TestSubject {
+	t.Helper()
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip(err)
+	}
+	return TestSubject{Name: "sh", Path: sh, Args: []string{"-c", script, "{src}", "{dst}"}}
+}
+
+func TestRunSubject(t *testing.T) {
+	cp, err := exec.LookPath("cp")
+	if err != nil {
+		t.Skip(err)
+	}
+	truePath, err := exec.LookPath("true")
+	if err != nil {
+		t.Skip(err)
+	}
+	src := testSource(t, 100*KB)
+
+	tests := []struct {
+		name      string
+		prog      TestSubject
+		completed int
+		wantErr   string
+	}{
+		{"cp", TestSubject{Name: "cp", Path: cp}, 20, ""},
+		// Ничего не записала — это ошибка, а не бесконечная скорость
+		{"true", TestSubject{Name: "true", Path: truePath}, 0, "iteration 1: destination missing"},
+		{"short copy", shSubject(t, `head -c 1000 "$0" > "$1"`), 0, "iteration 1: size mismatch"},
+		{"corrupted copy", shSubject(t, `cp "$0" "$1" && printf X | dd of="$1" bs=1 seek=5 conv=notrunc 2>/dev/null`), 0, "iteration 1: checksum mismatch"},
+		{"exit status", shSubject(t, `echo no space >&2; exit 3`), 0, "iteration 1: exit status 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "dst.bin")
+			res := runSubject(tt.prog, src, dst, 20, CacheHot)
+			if res.Completed() != tt.completed {
+				t.Errorf("completed %d, want %d", res.Completed(), tt.completed)
+			}
+			if tt.wantErr == "" && res.Err != nil {
+				t.Errorf("err %v, want nil", res.Err)
+			}
+			if tt.wantErr != "" && (res.Err == nil || !strings.Contains(res.Err.Error(), tt.wantErr)) {
+				t.Errorf("err %v, want %q", res.Err, tt.wantErr)
+			}
+			if res.Err == nil && res.Duration <= 0 {
+				t.Errorf("duration %v for a successful run", res.Duration)
+			}
+			if _, err := os.Stat(dst); !os.IsNotExist(err) {
+				t.Error("destination left behind")
+			}
+		})
+	}
+}
+
+// Копия портится начиная со второго запуска: ошибку находит первая итерация
+// выборки после первой, номер которой задает зерно.
+func TestRunSubjectSampledIteration(t *testing.T) {
+	src := testSource(t, 10*KB)
+	counter := filepath.Join(t.TempDir(), "runs")
+	prog := shSubject(t, `cp "$0" "$1" && echo >> `+counter+` && [ $(wc -l < `+counter+`) -eq 1 ] || printf X >> "$1"`)
+
+	const iterations = 100
+	sample := newVerifySample(seed)
+	want := 0
+	for i := 0; i < iterations; i++ {
+		if sample.Check(i) && i > 0 {
+			want = i + 1
+			break
+		}
+	}
+	if want == 0 {
+		t.Fatal("no iteration after the first one is sampled")
+	}
+
+	res := runSubject(prog, src, filepath.Join(t.TempDir(), "dst.bin"), iterations, CacheHot)
+	if res.Err == nil || !strings.HasPrefix(res.Err.Error(), fmt.Sprintf("iteration %d: size mismatch", want)) {
+		t.Fatalf("err %v, want a size mismatch at iteration %d", res.Err, want)
+	}
+	// Испорченные копии до проверки уже засчитаны — проверка выборочная
+	if res.Completed() != want-1 {
+		t.Errorf("completed %d, want %d", res.Completed(), want-1)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// coldCacheSupported На Linux холодный режим доступен.
+const coldCacheSupported = true
+
+// dropCachesPath Глобальный сброс page cache (только для root).
+const dropCachesPath = "/proc/sys/vm/drop_caches"
+
+// evictCache Вытесняет файлы из page cache.
+// С правами root используется drop_caches, иначе posix_fadvise(POSIX_FADV_DONTNEED)
+// для каждого файла. Несуществующие файлы пропускаются.
+func evictCache(paths ...string) error {
+	if os.Geteuid() == 0 {
+		unix.Sync()
+		if err := os.WriteFile(dropCachesPath, []byte("1"), 0); err == nil {
+			return nil
+		}
+		// Например, в контейнере /proc/sys смонтирован только для чтения — пробуем fadvise
+	}
+
+	for _, path := range paths {
+		if err := fadviseDontNeed(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropAllCaches Сбрасывает page cache и кэши dentry/inode (drop_caches = 3).
+// Для деревьев каталогов (ls -R, rm -r) важны именно dentry и inode, а их
+// fadvise не трогает, поэтому без root холодный режим для них невозможен.
+func dropAllCaches() error {
+	if os.Geteuid() != 0 {
+		return errors.New("dropping dentry and inode caches requires root")
+	}
+	unix.Sync()
+	return os.WriteFile(dropCachesPath, []byte("3"), 0)
+}
+
+// fadviseDontNeed Сбрасывает грязные страницы файла на диск и просит ядро выкинуть его из кэша.
+// DONTNEED не трогает грязные страницы, поэтому сначала нужен fdatasync.
+func fadviseDontNeed(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	fd := int(f.Fd())
+	if err := unix.Fdatasync(fd); err != nil {
+		return err
+	}
+	return unix.Fadvise(fd, 0, 0, unix.FADV_DONTNEED)
+}
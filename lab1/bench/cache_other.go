@@ -0,0 +1,18 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// coldCacheSupported Управлять page cache умеем только на Linux.
+const coldCacheSupported = false
+
+// evictCache Заглушка для остальных платформ.
+func evictCache(paths ...string) error {
+	return errors.New("cache eviction is not supported on this platform")
+}
+
+// dropAllCaches Заглушка для остальных платформ.
+func dropAllCaches() error {
+	return errors.New("cache eviction is not supported on this platform")
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Сценарии параллельной нагрузки
+const (
+	ScenarioSameFile  = "same-file"  // все потоки читают один исходник, пишут каждый в свой каталог
+	ScenarioDiffFiles = "diff-files" // у каждого потока свой исходник и свой каталог
+	ScenarioSameDir   = "same-dir"   // у каждого потока свой исходник, все пишут в один каталог
+	ScenarioAll       = "all"
+)
+
+var scenarios = []string{ScenarioSameFile, ScenarioDiffFiles, ScenarioSameDir}
+
+// ConcurrentResult Итог одного сценария с N параллельными потоками.
+type ConcurrentResult struct {
+	Wall      time.Duration   // от начала первой копии до конца последней, без проверок копий
+	Copies    int             // сколько копий завершилось успешно во всех потоках
+	Latencies []time.Duration // время каждой отдельной копии
+	Err       error           // первая ошибка среди потоков
+	Stderr    string          // stderr потока с первой ошибкой
+}
+
+// concurrentScenarios Разбирает значение --scenario.
+func concurrentScenarios(name string) ([]string, error) {
+	if name == ScenarioAll {
+		return scenarios, nil
+	}
+	for _, s := range scenarios {
+		if s == name {
+			return []string{s}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown scenario %q (want same-file, diff-files, same-dir or all)", name)
+}
+
+// prepareStreams Готовит исходники и пути приемников для сценария.
+// extra — дополнительные исходники (по одному на поток), созданные заранее.
+// Возвращает функцию, удаляющую созданные каталоги.
+func prepareStreams(scenario, name string, src SourceFile, extra []SourceFile) ([]SourceFile, []string, func(), error) {
+	n := len(extra)
+	srcs := make([]SourceFile, n)
+	dsts := make([]string, n)
+	var dirs []string
+
+	cleanup := func() {
+		for _, d := range dirs {
+			os.RemoveAll(d)
+		}
+	}
+
+	shared := filepath.Join(dstDir, fmt.Sprintf("bench_dst_%s_shared", name))
+	for i := 0; i < n; i++ {
+		dir := shared
+		if scenario != ScenarioSameDir {
+			dir = filepath.Join(dstDir, fmt.Sprintf("bench_dst_%s_stream%d", name, i))
+		}
+		if i == 0 || scenario != ScenarioSameDir {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				cleanup()
+				return nil, nil, nil, err
+			}
+			dirs = append(dirs, dir)
+		}
+
+		srcs[i] = extra[i]
+		if scenario == ScenarioSameFile {
+			srcs[i] = src
+		}
+		dsts[i] = filepath.Join(dir, fmt.Sprintf("copy%d.bin", i))
+	}
+	return srcs, dsts, cleanup, nil
+}
+
+// runConcurrent Запускает по одному потоку runStream на каждую пару исходник/приемник
+// одновременно и собирает общий результат. Отложенные потоками копии сверяются
+// с исходниками уже после того, как закончились все потоки.
+func runConcurrent(prog TestSubject, srcs []SourceFile, dsts []string, iterations int) ConcurrentResult {
+	results := make([]RunResult, len(srcs))
+	kept := make([][]keptCopy, len(srcs))
+
+	var wg sync.WaitGroup
+	for i := range srcs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], kept[i] = runStream(prog, srcs[i], dsts[i], iterations)
+		}(i)
+	}
+	wg.Wait()
+
+	var res ConcurrentResult
+	var window Span
+	for i, r := range results {
+		for _, k := range kept[i] {
+			if err := verifyCopy(k.path, srcs[i].Size, srcs[i].Checksum); err != nil && r.Err == nil {
+				r.Err = fmt.Errorf("iteration %d: %v", k.iteration, err)
+			}
+			os.Remove(k.path)
+		}
+
+		window = window.Join(r.Window)
+		res.Copies += len(r.Latencies)
+		res.Latencies = append(res.Latencies, r.Latencies...)
+		if r.Err != nil && res.Err == nil {
+			res.Err = r.Err
+			res.Stderr = r.Stderr
+		}
+	}
+	res.Wall = window.Duration()
+	return res
+}
+
+// keptCopy Копия, отложенная для проверки после прогона.
+type keptCopy struct {
+	iteration int // номер итерации, с 1
+	path      string
+}
+
+// runStream Один поток сценария: как runSubject в горячем режиме, но без проверок
+// между итерациями — в общем окне времени они замедлили бы соседние потоки.
+// Копии, выпавшие в выборку, переименовываются и возвращаются для проверки,
+// остальные удаляются.
+func runStream(prog TestSubject, src SourceFile, dst string, iterations int) (RunResult, []keptCopy) {
+	var res RunResult
+	var kept []keptCopy
+	sample := newVerifySample(seed)
+
+	for i := 0; i < iterations; i++ {
+		span, u, stderr, err := runIteration(prog, prog.Expand(src.Path, dst))
+		if err != nil {
+			res.Err = fmt.Errorf("iteration %d: %v", i+1, err)
+			res.Stderr = stderr
+			os.Remove(dst)
+			break
+		}
+		res.add(span, u)
+
+		if !sample.Check(i) {
+			os.Remove(dst)
+			continue
+		}
+		k := keptCopy{iteration: i + 1, path: fmt.Sprintf("%s.verify%d", dst, i+1)}
+		if err := os.Rename(dst, k.path); err != nil {
+			// Приемника нет или он не файл — проверка скажет, что не так
+			k.path = dst
+			kept = append(kept, k)
+			break
+		}
+		kept = append(kept, k)
+	}
+	return res, kept
+}
+
+// percentile Возвращает p-й перцентиль (0..1) из отсортированного среза.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// printConcurrent Прогоняет сценарии для одного размера файла, печатает таблицу
+// и добавляет результаты в отчет (набор "copy/<сценарий> xN").
+// Итерации делятся между потоками, чтобы общий объем совпадал с однопоточным прогоном.
+// Возвращает true, если прогон остановлен из-за --fail-fast.
+func printConcurrent(out io.Writer, report *Report, name string, src SourceFile, extra []SourceFile,
+	modes, scens []string, iterations int, failures *[]string) bool {
+
+	streams := len(extra)
+	perStream := iterations / streams
+	if perStream == 0 {
+		perStream = 1
+	}
+
+	w := tabwriter.NewWriter(out, 10, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "CONCURRENT: %d streams x %d iterations\n", streams, perStream)
+	fmt.Fprintln(w, "FILE SIZE\tPROGRAM\tSCENARIO\tCACHE\tCOPIES\tWALL\tAGGREGATE\tLAT AVG\tLAT P95\tLAT MAX")
+	fmt.Fprintln(w, "---------\t-------\t--------\t-----\t------\t----\t---------\t-------\t-------\t-------")
+
+	defer w.Flush()
+
+	// Сброс кэша одним потоком (fadvise общего исходника, глобальный drop_caches)
+	// попадал бы в замер соседних, поэтому холодный режим здесь не прогоняется
+	if slices.Contains(modes, CacheCold) {
+		fmt.Fprintln(w, "(cold runs skipped: streams share the page cache)")
+	}
+
+	for _, scenario := range scens {
+		for _, prog := range programs {
+			for _, mode := range modes {
+				if mode == CacheCold {
+					continue
+				}
+				srcs, dsts, cleanup, err := prepareStreams(scenario, name, src, extra)
+				if err != nil {
+					fmt.Printf("Error preparing scenario %s: %v\n", scenario, err)
+					continue
+				}
+
+				time.Sleep(100 * time.Millisecond)
+				res := runConcurrent(prog, srcs, dsts, perStream)
+				cleanup()
+
+				// Для отчета время — общее окно копий (wall), так что скорость в нем совпадает с AGGREGATE
+				report.Results = append(report.Results, Result{
+					Suite:      fmt.Sprintf("%s/%s x%d", SuiteCopy, scenario, streams),
+					SizeName:   name,
+					Size:       src.Size,
+					Program:    prog.Name,
+					Mode:       mode,
+					Iterations: perStream * streams,
+					RunResult:  RunResult{Duration: res.Wall, Latencies: res.Latencies, Err: res.Err, Stderr: res.Stderr},
+				})
+
+				sort.Slice(res.Latencies, func(i, j int) bool { return res.Latencies[i] < res.Latencies[j] })
+				var sum time.Duration
+				for _, l := range res.Latencies {
+					sum += l
+				}
+				var avg time.Duration
+				if len(res.Latencies) > 0 {
+					avg = sum / time.Duration(len(res.Latencies))
+				}
+
+				totalMB := float64(src.Size) * float64(res.Copies) / float64(MB)
+				aggregate := 0.0
+				if s := res.Wall.Seconds(); s > 0.0001 {
+					aggregate = totalMB / s
+				}
+				aggCol := fmt.Sprintf("%.2f MB/s", aggregate)
+				if res.Err != nil {
+					aggCol = "FAILED"
+					*failures = append(*failures, failureLine(fmt.Sprintf("%s %s %s (%s)", name, prog.Name, scenario, mode),
+						RunResult{Err: res.Err, Stderr: res.Stderr}))
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%v\t%s\t%v\t%v\t%v\n",
+					name,
+					prog.Name,
+					scenario,
+					mode,
+					res.Copies,
+					res.Wall.Round(time.Millisecond),
+					aggCol,
+					avg.Round(time.Microsecond),
+					percentile(res.Latencies, 0.95).Round(time.Microsecond),
+					percentile(res.Latencies, 1).Round(time.Microsecond),
+				)
+				if res.Err != nil && failFast {
+					fmt.Fprintln(w)
+					return true
+				}
+			}
+		}
+	}
+	fmt.Fprintln(w)
+	return false
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunConcurrent(t *testing.T) {
+	cp, err := exec.LookPath("cp")
+	if err != nil {
+		t.Skip(err)
+	}
+	truePath, err := exec.LookPath("true")
+	if err != nil {
+		t.Skip(err)
+	}
+	src := testSource(t, 100*KB)
+
+	tests := []struct {
+		name    string
+		prog    TestSubject
+		wantErr string
+	}{
+		{"cp", TestSubject{Name: "cp", Path: cp}, ""},
+		{"true", TestSubject{Name: "true", Path: truePath}, "iteration 1: destination missing"},
+		// Проверка идет после всех потоков, но ошибку по-прежнему находит
+		{"corrupted copy", shSubject(t, `cp "$0" "$1" && printf X | dd of="$1" bs=1 seek=5 conv=notrunc 2>/dev/null`), "iteration 1: checksum mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const streams, iterations = 3, 10
+			dir := t.TempDir()
+			srcs := make([]SourceFile, streams)
+			dsts := make([]string, streams)
+			for i := range srcs {
+				srcs[i] = src
+				dsts[i] = filepath.Join(dir, fmt.Sprintf("copy%d.bin", i))
+			}
+
+			res := runConcurrent(tt.prog, srcs, dsts, iterations)
+			if tt.wantErr == "" && res.Err != nil {
+				t.Fatalf("err %v, want nil", res.Err)
+			}
+			if tt.wantErr != "" && (res.Err == nil || !strings.Contains(res.Err.Error(), tt.wantErr)) {
+				t.Fatalf("err %v, want %q", res.Err, tt.wantErr)
+			}
+			if left, _ := os.ReadDir(dir); len(left) != 0 {
+				t.Errorf("copies left behind: %v", left)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+
+			if res.Copies != streams*iterations || len(res.Latencies) != res.Copies {
+				t.Errorf("copies %d, latencies %d; want %d", res.Copies, len(res.Latencies), streams*iterations)
+			}
+			// Окно копий не короче самой долгой копии и не длиннее их суммы
+			var sum, max time.Duration
+			for _, l := range res.Latencies {
+				sum += l
+				if l > max {
+					max = l
+				}
+			}
+			if res.Wall < max || res.Wall > sum {
+				t.Errorf("wall %v, want between %v and %v", res.Wall, max, sum)
+			}
+		})
+	}
+}
+
+func TestSpanJoin(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	at := func(a, b int) Span {
+		return Span{Start: t0.Add(time.Duration(a) * time.Second), End: t0.Add(time.Duration(b) * time.Second)}
+	}
+	tests := []struct {
+		a, b, want Span
+	}{
+		{Span{}, at(1, 2), at(1, 2)},
+		{at(1, 2), Span{}, at(1, 2)},
+		{at(1, 2), at(3, 5), at(1, 5)},
+		{at(3, 5), at(1, 2), at(1, 5)},
+		{at(1, 5), at(2, 3), at(1, 5)},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Join(tt.b); got != tt.want {
+			t.Errorf("%v.Join(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"runtime"
+)
+
+// environment Собирает отпечаток окружения: ОС, процессор, Go, файловые системы
+// рабочих каталогов и хэши тестируемых программ. Без него старые таблицы
+// невозможно сравнивать между собой.
+func environment(subjects []*TestSubject) [][2]string {
+	env := [][2]string{
+		{"OS", systemInfo()},
+		{"CPU", fmt.Sprintf("%s (%d logical)", cpuModel(), runtime.NumCPU())},
+		{"GOMAXPROCS", fmt.Sprint(runtime.GOMAXPROCS(0))},
+		{"Go", fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH)},
+		{"Source mount", mountInfo(srcDir)},
+		{"Destination mount", mountInfo(dstDir)},
+	}
+
+	for _, prog := range subjects {
+		hash := "unavailable"
+		if sum, err := fileChecksum(prog.Path); err == nil {
+			hash = "sha256:" + hex.EncodeToString(sum[:])
+		}
+		env = append(env, [2]string{prog.Name + " binary", hash})
+	}
+	return env
+}
@@ -0,0 +1,123 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// systemInfo Аналог uname -a.
+func systemInfo() string {
+	var u unix.Utsname
+	if err := unix.Uname(&u); err != nil {
+		return "unknown"
+	}
+	return strings.Join([]string{
+		unix.ByteSliceToString(u.Sysname[:]),
+		unix.ByteSliceToString(u.Nodename[:]),
+		unix.ByteSliceToString(u.Release[:]),
+		unix.ByteSliceToString(u.Version[:]),
+		unix.ByteSliceToString(u.Machine[:]),
+	}, " ")
+}
+
+// cpuModel Модель процессора из /proc/cpuinfo.
+func cpuModel() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return "unknown"
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return "unknown"
+}
+
+// mountInfo Тип ФС, точка монтирования и опции для каталога path по /proc/self/mountinfo.
+func mountInfo(path string) string {
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+	}
+	if err != nil {
+		return "unknown"
+	}
+
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return fsType(path)
+	}
+	defer f.Close()
+	return parseMountInfo(f, abs, fsType(path))
+}
+
+// parseMountInfo Ищет в mountinfo самую длинную точку монтирования, под которой
+// лежит абсолютный путь abs. Если такой нет, возвращает fallback.
+func parseMountInfo(r io.Reader, abs, fallback string) string {
+	// Формат: id parent maj:min root mountpoint opts [optional...] - fstype source superopts
+	best, result := -1, fallback
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		pre, post, ok := strings.Cut(scanner.Text(), " - ")
+		if !ok {
+			continue
+		}
+		fields, tail := strings.Fields(pre), strings.Fields(post)
+		if len(fields) < 6 || len(tail) < 3 {
+			continue
+		}
+		mnt := unescapeMount(fields[4])
+		if !underMount(abs, mnt) || len(mnt) < best {
+			continue
+		}
+		// При равной длине побеждает более поздняя запись — она монтирована поверх
+		best = len(mnt)
+		result = fmt.Sprintf("%s on %s (%s; %s)", tail[0], mnt, fields[5], tail[2])
+	}
+	return result
+}
+
+// underMount Лежит ли path внутри точки монтирования mnt.
+func underMount(path, mnt string) bool {
+	if mnt == "/" || path == mnt {
+		return true
+	}
+	return strings.HasPrefix(path, mnt+"/")
+}
+
+// unescapeMount Раскрывает восьмеричные escape-последовательности (\040 — пробел) из mountinfo.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		// Ровно три восьмеричные цифры, иначе обратная косая черта остается как есть
+		if s[i] == '\\' && i+3 < len(s) && isOctal(s[i+1]) && isOctal(s[i+2]) && isOctal(s[i+3]) {
+			b.WriteByte((s[i+1]-'0')<<6 | (s[i+2]-'0')<<3 | (s[i+3] - '0'))
+			i += 3
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const testMountInfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw,errors=remount-ro
+25 22 0:21 / /data rw,relatime shared:2 - xfs /dev/sdb1 rw,attr2
+26 25 0:22 / /data/fast rw,nosuid - tmpfs tmpfs rw,size=1048576k
+27 22 0:23 / /data2 rw - ext4 /dev/sdc1 rw
+28 22 0:24 / /mnt/my\040disk rw - vfat /dev/sdd1 rw,fmask=0022
+29 22 0:25 / /mnt/tab\011dir rw - ext4 /dev/sde1 rw
+30 26 0:26 / /data/fast rw - overlay overlay rw,lowerdir=/l
+broken line without separator
+31 22 0:27 / /short - ext4
+`
+
+func TestParseMountInfo(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/user", "ext4 on / (rw,relatime; rw,errors=remount-ro)"},
+		{"/data", "xfs on /data (rw,relatime; rw,attr2)"},
+		{"/data/bench", "xfs on /data (rw,relatime; rw,attr2)"},
+		// Самая длинная точка монтирования; при равных — смонтированная позже
+		{"/data/fast/src", "overlay on /data/fast (rw; rw,lowerdir=/l)"},
+		// /data2 — не подкаталог /data
+		{"/data2/x", "ext4 on /data2 (rw; rw)"},
+		{"/mnt/my disk/x", "vfat on /mnt/my disk (rw; rw,fmask=0022)"},
+		{"/mnt/tab\tdir", "ext4 on /mnt/tab\tdir (rw; rw)"},
+		{"/short", "ext4 on / (rw,relatime; rw,errors=remount-ro)"},
+	}
+	for _, tt := range tests {
+		if got := parseMountInfo(strings.NewReader(testMountInfo), tt.path, "fallback"); got != tt.want {
+			t.Errorf("parseMountInfo(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	if got := parseMountInfo(strings.NewReader(""), "/x", "fallback"); got != "fallback" {
+		t.Errorf("empty mountinfo: %q, want fallback", got)
+	}
+}
+
+func TestUnderMount(t *testing.T) {
+	tests := []struct {
+		path, mnt string
+		want      bool
+	}{
+		{"/anything", "/", true},
+		{"/data", "/data", true},
+		{"/data/x", "/data", true},
+		{"/data2", "/data", false},
+		{"/dat", "/data", false},
+	}
+	for _, tt := range tests {
+		if got := underMount(tt.path, tt.mnt); got != tt.want {
+			t.Errorf("underMount(%q, %q) = %v, want %v", tt.path, tt.mnt, got, tt.want)
+		}
+	}
+}
+
+func TestUnescapeMount(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"/plain", "/plain"},
+		{`/my\040disk`, "/my disk"},
+		{`/a\011b\012c`, "/a\tb\nc"},
+		{`/back\134slash`, `/back\slash`},
+		{`/end\040`, "/end "},
+		{`/short\04`, `/short\04`},
+		{`/not\09octal`, `/not\09octal`},
+		{`/trailing\`, `/trailing\`},
+	}
+	for _, tt := range tests {
+		if got := unescapeMount(tt.in); got != tt.want {
+			t.Errorf("unescapeMount(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
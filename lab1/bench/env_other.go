@@ -0,0 +1,20 @@
+//go:build !linux
+
+package main
+
+import "runtime"
+
+// systemInfo На остальных платформах — только ОС и архитектура.
+func systemInfo() string {
+	return runtime.GOOS + " " + runtime.GOARCH
+}
+
+// cpuModel Модель процессора на этой платформе не определяется.
+func cpuModel() string {
+	return "unknown"
+}
+
+// mountInfo Опции монтирования на этой платформе не определяются.
+func mountInfo(path string) string {
+	return fsType(path)
+}
@@ -0,0 +1,98 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"golang.org/x/sys/unix"
+)
+
+// fsMagic Известные значения f_type из statfs(2)
+var fsMagic = map[int64]string{
+	0xEF53:     "ext4",
+	0x01021994: "tmpfs",
+	0x58465342: "xfs",
+	0x9123683E: "btrfs",
+	0x794C7630: "overlayfs",
+	0x6969:     "nfs",
+	0x2FC12FC1: "zfs",
+	0xF2F52010: "f2fs",
+	0x4D44:     "vfat",
+	0x65735546: "fuse",
+	0x5346544E: "ntfs",
+	0x858458F6: "ramfs",
+}
+
+// fsType Возвращает тип файловой системы, на которой лежит path.
+func fsType(path string) string {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return "unknown"
+	}
+	if name, ok := fsMagic[int64(st.Type)]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%x", st.Type)
+}
+
+// mountScratch Создает временный каталог и монтирует в него tmpfs или
+// loopback-образ ext4 размером sizeMB. Требует прав root.
+// Возвращает каталог и функцию, которая размонтирует и удалит все созданное.
+func mountScratch(kind string, sizeMB int) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "bench_scratch_")
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch kind {
+	case ScratchTmpfs:
+		opts := fmt.Sprintf("size=%dm", sizeMB)
+		if err := unix.Mount("tmpfs", dir, "tmpfs", 0, opts); err != nil {
+			os.Remove(dir)
+			return "", nil, fmt.Errorf("mount tmpfs: %v (root required)", err)
+		}
+		return dir, func() {
+			// MNT_DETACH: прерванный копировщик может еще держать файлы открытыми
+			unix.Unmount(dir, unix.MNT_DETACH)
+			os.Remove(dir)
+		}, nil
+
+	case ScratchLoop:
+		// Образ кладем рядом с точкой монтирования, он разреженный
+		image := dir + ".img"
+		removeAll := func() {
+			os.Remove(image)
+			os.Remove(dir)
+		}
+
+		f, err := os.Create(image)
+		if err == nil {
+			err = f.Truncate(int64(sizeMB) * MB)
+			f.Close()
+		}
+		if err != nil {
+			removeAll()
+			return "", nil, err
+		}
+
+		if out, err := exec.Command("mkfs.ext4", "-q", "-F", image).CombinedOutput(); err != nil {
+			removeAll()
+			return "", nil, fmt.Errorf("mkfs.ext4: %v: %s", err, out)
+		}
+		if out, err := exec.Command("mount", "-o", "loop", image, dir).CombinedOutput(); err != nil {
+			removeAll()
+			return "", nil, fmt.Errorf("mount loop: %v: %s (root required)", err, out)
+		}
+		return dir, func() {
+			// loop-устройство освободится само (autoclear) после отмонтирования
+			unix.Unmount(dir, unix.MNT_DETACH)
+			removeAll()
+		}, nil
+	}
+
+	os.Remove(dir)
+	return "", nil, fmt.Errorf("unknown scratch kind %q", kind)
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// fsType Определять тип файловой системы умеем только на Linux.
+func fsType(path string) string {
+	return "unknown"
+}
+
+// mountScratch Монтирование доступно только на Linux.
+func mountScratch(kind string, sizeMB int) (string, func(), error) {
+	return "", nil, errors.New("scratch areas are not supported on this platform")
+}
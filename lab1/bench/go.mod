@@ -1,3 +1,5 @@
 module bench
 
 go 1.25
+
+require golang.org/x/sys v0.41.0
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"math/rand"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"text/tabwriter"
 	"time"
 )
@@ -25,9 +31,151 @@ const (
 	SmallFileLimit = 1 * MB
 )
 
+// Режимы кэша
+const (
+	CacheHot  = "hot"  // данные остаются в page cache между итерациями
+	CacheCold = "cold" // перед каждой итерацией кэш сбрасывается
+	CacheBoth = "both" // прогоняем оба режима
+)
+
+// Временные области для самодостаточного режима
+const (
+	ScratchNone  = "none"
+	ScratchTmpfs = "tmpfs" // tmpfs в памяти
+	ScratchLoop  = "loop"  // loopback-образ ext4
+)
+
+// Опции командной строки
+var (
+	cacheMode   string        // --cache
+	patternName string        // --pattern
+	seed        int64         // --seed
+	streams     int           // --streams
+	scenario    string        // --scenario
+	srcDir      string        // --src-dir
+	dstDir      string        // --dst-dir
+	scratch     string        // --scratch
+	scratchSize int           // --scratch-size
+	suiteName   string        // --suite
+	htmlReport  string        // --html
+	timeout     time.Duration // --timeout
+	failFast    bool          // --fail-fast
+)
+
+func init() {
+	flag.StringVar(&cacheMode, "cache", CacheHot, "Режим кэша: hot, cold или both")
+	flag.StringVar(&patternName, "pattern", PatternRandom, "Содержимое исходных файлов: random, zeros, repeating, text или sparse")
+	flag.Int64Var(&seed, "seed", 1, "Зерно генератора данных")
+	flag.IntVar(&streams, "streams", 1, "Число параллельных копий для сценариев нагрузки (1 — без них)")
+	flag.StringVar(&scenario, "scenario", ScenarioAll, "Сценарий нагрузки: same-file, diff-files, same-dir или all")
+	flag.StringVar(&srcDir, "src-dir", ".", "Каталог для исходных файлов")
+	flag.StringVar(&dstDir, "dst-dir", ".", "Каталог для копий")
+	flag.StringVar(&scratch, "scratch", ScratchNone, "Временная ФС: none, tmpfs или loop; используется для src/dst, не заданных явно")
+	flag.IntVar(&scratchSize, "scratch-size", 1024, "Размер временной ФС в МБ")
+	flag.StringVar(&suiteName, "suite", SuiteCopy, "Что тестировать: copy, cat, ls, rm или all")
+	flag.StringVar(&htmlReport, "html", "", "Сохранить HTML-отчет с графиками в указанный файл")
+	flag.DurationVar(&timeout, "timeout", time.Minute, "Предельное время одной итерации (0 — без ограничения)")
+	flag.BoolVar(&failFast, "fail-fast", false, "Остановить весь прогон после первой ошибки")
+}
+
+// TestSubject Тестируемая программа.
+// Args — шаблон аргументов: {src} и {dst} заменяются путями входа и выхода.
+// Пустой шаблон означает "prog SRC DST", как у копировщиков.
 type TestSubject struct {
 	Name string
 	Path string
+	Args []string
+}
+
+// Expand Подставляет пути в шаблон аргументов.
+func (t TestSubject) Expand(src, dst string) []string {
+	if len(t.Args) == 0 {
+		return []string{src, dst}
+	}
+	args := make([]string, len(t.Args))
+	for i, a := range t.Args {
+		a = strings.ReplaceAll(a, "{src}", src)
+		args[i] = strings.ReplaceAll(a, "{dst}", dst)
+	}
+	return args
+}
+
+// SourceFile Исходный файл и то, что должно получиться после копирования.
+type SourceFile struct {
+	Path     string
+	Size     int64
+	Checksum Checksum
+}
+
+// RunResult Итог прогона одной программы в одном режиме.
+// Время и ресурсы учитываются только по успешно завершенным итерациям.
+type RunResult struct {
+	Duration  time.Duration   // суммарное время копирования
+	Usage     Usage           // суммарные ресурсы
+	Latencies []time.Duration // время каждой успешной копии
+	Window    Span            // от начала первой успешной копии до конца последней
+	Err       error           // ошибка запуска, таймаут или неверный результат копирования
+	Stderr    string          // stderr упавшей итерации
+}
+
+// Completed Сколько итераций завершилось успешно.
+func (r RunResult) Completed() int {
+	return len(r.Latencies)
+}
+
+// add Учитывает успешно завершенную итерацию.
+func (r *RunResult) add(span Span, u Usage) {
+	r.Duration += span.Duration()
+	r.Usage.Add(u)
+	r.Latencies = append(r.Latencies, span.Duration())
+	r.Window = r.Window.Join(span)
+}
+
+// Span Интервал времени одного запуска.
+type Span struct {
+	Start, End time.Time
+}
+
+// Duration Длительность интервала.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Join Наименьший интервал, покрывающий оба. Нулевой интервал не учитывается.
+func (s Span) Join(o Span) Span {
+	switch {
+	case s.Start.IsZero():
+		return o
+	case o.Start.IsZero():
+		return s
+	}
+	if o.Start.Before(s.Start) {
+		s.Start = o.Start
+	}
+	if o.End.After(s.End) {
+		s.End = o.End
+	}
+	return s
+}
+
+// MaxStderr Сколько байт stderr упавшей программы сохраняется для отчета.
+const MaxStderr = 4 * KB
+
+// limitedBuffer Буфер, молча отбрасывающий все сверх limit байт.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
 }
 
 // ПУТИ (относительно папки bench/)
@@ -49,7 +197,70 @@ var fileSizes = []struct {
 }
 
 func main() {
-	for i, prog := range programs {
+	flag.Parse()
+
+	modes, err := cacheModes(cacheMode)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if !validPattern(patternName) {
+		fmt.Printf("Error: unknown pattern %q (want random, zeros, repeating, text or sparse)\n", patternName)
+		return
+	}
+	if streams < 1 {
+		fmt.Println("Error: --streams must be at least 1")
+		return
+	}
+	scens, err := concurrentScenarios(scenario)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	if scratch != ScratchNone {
+		dir, cleanup, err := mountScratch(scratch, scratchSize)
+		if err != nil {
+			fmt.Println("Error creating scratch area:", err)
+			return
+		}
+		defer cleanup()
+
+		// Не оставляем смонтированную ФС, если прогон прервали
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			cleanup()
+			os.Exit(1)
+		}()
+
+		// Временная ФС заменяет только те каталоги, которые не заданы явно,
+		// так что --scratch=tmpfs --dst-dir=. дает копирование tmpfs -> диск
+		explicit := map[string]bool{}
+		flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+		if !explicit["src-dir"] {
+			srcDir = dir
+		}
+		if !explicit["dst-dir"] {
+			dstDir = dir
+		}
+	}
+	for _, dir := range []string{srcDir, dstDir} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			fmt.Printf("Error: %s is not a directory\n", dir)
+			return
+		}
+	}
+
+	suites, err := selectSuites(suiteName)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	subjects := selectedSubjects(suites)
+
+	for _, prog := range subjects {
 		absPath, err := filepath.Abs(prog.Path)
 		if err != nil {
 			fmt.Println("Error path:", err)
@@ -59,16 +270,79 @@ func main() {
 			fmt.Printf("ERROR: Program not found: %s\nPath: %s\n", prog.Name, absPath)
 			return
 		}
-		programs[i].Path = absPath
+		prog.Path = absPath
 	}
 
+	report := &Report{
+		Suite:   suiteName,
+		Started: time.Now(),
+		Settings: [][2]string{
+			{"Suite", suiteName},
+			{"Cache mode", cacheMode},
+			{"Data pattern", fmt.Sprintf("%s (seed %d)", patternName, seed)},
+			{"Source dir", fmt.Sprintf("%s (%s)", srcDir, fsType(srcDir))},
+			{"Destination dir", fmt.Sprintf("%s (%s)", dstDir, fsType(dstDir))},
+		},
+	}
+	if streams > 1 {
+		report.Settings = append(report.Settings, [2]string{"Concurrent streams", fmt.Sprintf("%d (%s)", streams, scenario)})
+	}
+	report.Environment = environment(subjects)
+
 	fmt.Println("=======================================================================")
 	fmt.Println("BENCHMARK STARTED")
 	fmt.Printf("Small files (<= 1MB): Fixed %d iterations\n", IterationsSmall)
 	fmt.Printf("Large files (> 1MB):  Target volume ~%d MB\n", TargetVolumeLarge/MB)
+	for _, kv := range report.Settings {
+		fmt.Printf("%s: %s\n", kv[0], kv[1])
+	}
+	fmt.Println("-----------------------------------------------------------------------")
+	fmt.Println("ENVIRONMENT")
+	for _, kv := range report.Environment {
+		fmt.Printf("%s: %s\n", kv[0], kv[1])
+	}
 	fmt.Println("=======================================================================")
 	fmt.Println()
 
+	// Программы, выдавшие неверный результат, с причинами
+	var failures []string
+	// Выставляется при --fail-fast после первой ошибки
+	stop := false
+
+	if suites[SuiteCopy] {
+		stop = runCopySuite(report, modes, scens, &failures)
+	}
+	for _, suite := range toolSuites {
+		if stop || !suites[suite.Name] {
+			continue
+		}
+		stop = runToolSuite(suite, report, modes, &failures)
+	}
+
+	if len(failures) > 0 {
+		fmt.Println("FAILED:")
+		for _, f := range failures {
+			fmt.Println("  " + f)
+		}
+		if stop {
+			fmt.Println("Stopped after the first failure (--fail-fast).")
+		}
+	}
+
+	if htmlReport != "" {
+		if err := writeHTMLReport(htmlReport, report); err != nil {
+			fmt.Println("Error writing HTML report:", err)
+		} else {
+			fmt.Println("HTML report:", htmlReport)
+		}
+	}
+
+	fmt.Println("Done.")
+}
+
+// runCopySuite Прогоняет копировщики на всех размерах файлов.
+// Возвращает true, если прогон остановлен из-за --fail-fast.
+func runCopySuite(report *Report, modes, scens []string, failures *[]string) bool {
 	// Настраиваем TabWriter:
 	// minwidth=10 (минимальная ширина колонки)
 	// tabwidth=0
@@ -77,8 +351,12 @@ func main() {
 	// flags=0 (без отладочных линий)
 	w := tabwriter.NewWriter(os.Stdout, 10, 0, 3, ' ', 0)
 
-	fmt.Fprintln(w, "FILE SIZE\tPROGRAM\tITERS\tDATA VOL\tTIME TOTAL\tSPEED")
-	fmt.Fprintln(w, "---------\t-------\t-----\t--------\t----------\t-----")
+	// Ресурсы (USER, SYS, ...) указаны в среднем на одну итерацию, MAXRSS — максимум
+	fmt.Fprintln(w, "FILE SIZE\tPROGRAM\tCACHE\tITERS\tDATA VOL\tTIME TOTAL\tSPEED\t"+usageHeader)
+	fmt.Fprintln(w, "---------\t-------\t-----\t-----\t--------\t----------\t-----\t"+usageRule)
+
+	// Выставляется при --fail-fast после первой ошибки
+	stop := false
 
 	for _, fs := range fileSizes {
 		var iterations int
@@ -91,88 +369,216 @@ func main() {
 			}
 		}
 
-		srcInfo := fmt.Sprintf("bench_src_%s.bin", fs.name)
-		dstInfo := fmt.Sprintf("bench_dst_%s.bin", fs.name)
+		srcInfo := filepath.Join(srcDir, fmt.Sprintf("bench_src_%s.bin", fs.name))
+		dstInfo := filepath.Join(dstDir, fmt.Sprintf("bench_dst_%s.bin", fs.name))
 
-		if err := createDummyFile(srcInfo, fs.size); err != nil {
+		src, err := prepareSource(srcInfo, fs.size, seed)
+		if err != nil {
 			fmt.Printf("Error creating source file: %v\n", err)
 			continue
 		}
 
+	progs:
 		for _, prog := range programs {
-			// Небольшая пауза для стабилизации ОС
-			time.Sleep(100 * time.Millisecond)
+			for _, mode := range modes {
+				// Небольшая пауза для стабилизации ОС
+				time.Sleep(100 * time.Millisecond)
 
-			start := time.Now()
+				res := runSubject(prog, src, dstInfo, iterations, mode)
+				report.Results = append(report.Results, Result{
+					Suite:      SuiteCopy,
+					SizeName:   fs.name,
+					Size:       fs.size,
+					Program:    prog.Name,
+					Mode:       mode,
+					Iterations: iterations,
+					RunResult:  res,
+				})
 
-			for i := 0; i < iterations; i++ {
-				cmd := exec.Command(prog.Path, srcInfo, dstInfo)
-				if err := cmd.Run(); err != nil {
-					fmt.Printf("\nError in %s: %v\n", prog.Name, err)
-					break
+				// Скорость считаем только по реально завершенным итерациям
+				completed := res.Completed()
+				totalMB := (float64(fs.size) * float64(completed)) / float64(MB)
+				seconds := res.Duration.Seconds()
+				speed := 0.0
+				if seconds > 0.0001 {
+					speed = totalMB / seconds
+				}
+				speedCol := fmt.Sprintf("%.2f MB/s", speed)
+				if res.Err != nil {
+					speedCol = "FAILED"
+					*failures = append(*failures, failureLine(fmt.Sprintf("%s %s (%s)", fs.name, prog.Name, mode), res))
 				}
-				// Удаляем копию сразу
-				os.Remove(dstInfo)
-			}
-
-			duration := time.Since(start)
 
-			totalMB := (float64(fs.size) * float64(iterations)) / float64(MB)
-			seconds := duration.Seconds()
-			speed := 0.0
-			if seconds > 0.0001 {
-				speed = totalMB / seconds
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%.1f MB\t%v\t%s\t%s\n",
+					fs.name,
+					prog.Name,
+					mode,
+					completed,
+					iterations,
+					totalMB,
+					res.Duration.Round(time.Millisecond),
+					speedCol,
+					usageColumns(res.Usage.PerIteration(completed)),
+				)
+				if res.Err != nil && failFast {
+					stop = true
+					break progs
+				}
 			}
-
-			fmt.Fprintf(w, "%s\t%s\t%d\t%.1f MB\t%v\t%.2f MB/s\n",
-				fs.name,
-				prog.Name,
-				iterations,
-				totalMB,
-				duration.Round(time.Millisecond),
-				speed,
-			)
 		}
 
 		// Разделитель между группами размеров (пустая строка для читаемости)
-		fmt.Fprintln(w, "\t\t\t\t\t")
+		fmt.Fprintln(w, "\t\t\t\t\t\t\t")
 
 		// Сбрасываем буфер на экран после каждого размера файла
 		w.Flush()
 
+		if stop {
+			os.Remove(srcInfo)
+			break
+		}
+
+		if streams > 1 {
+			// Для сценариев с разными файлами у каждого потока свой исходник со своим зерном
+			extra := []SourceFile{src}
+			for i := 1; i < streams; i++ {
+				path := filepath.Join(srcDir, fmt.Sprintf("bench_src_%s_%d.bin", fs.name, i))
+				s, err := prepareSource(path, fs.size, seed+int64(i))
+				if err != nil {
+					fmt.Printf("Error creating source file: %v\n", err)
+					break
+				}
+				extra = append(extra, s)
+			}
+			if len(extra) == streams {
+				stop = printConcurrent(os.Stdout, report, fs.name, src, extra, modes, scens, iterations, failures)
+			}
+			for _, s := range extra[1:] {
+				os.Remove(s.Path)
+			}
+		}
+
 		// Удаляем исходник
 		os.Remove(srcInfo)
+		if stop {
+			break
+		}
 	}
 
-	fmt.Println("Done.")
+	return stop
+}
+
+// cacheModes Разбирает значение --cache в список режимов для прогона.
+func cacheModes(mode string) ([]string, error) {
+	var modes []string
+	switch mode {
+	case CacheHot:
+		modes = []string{CacheHot}
+	case CacheCold:
+		modes = []string{CacheCold}
+	case CacheBoth:
+		modes = []string{CacheHot, CacheCold}
+	default:
+		return nil, fmt.Errorf("unknown cache mode %q (want hot, cold or both)", mode)
+	}
+
+	if mode != CacheHot && !coldCacheSupported {
+		return nil, fmt.Errorf("cold cache mode is not supported on this platform")
+	}
+	return modes, nil
 }
 
-func createDummyFile(filename string, size int64) error {
-	f, err := os.Create(filename)
+// prepareSource Создает исходный файл по текущему шаблону и считает его контрольную сумму.
+func prepareSource(path string, size int64, seed int64) (SourceFile, error) {
+	if err := createDummyFile(path, size, patternName, seed); err != nil {
+		return SourceFile{}, err
+	}
+	sum, err := fileChecksum(path)
 	if err != nil {
-		return err
+		os.Remove(path)
+		return SourceFile{}, err
 	}
-	defer f.Close()
+	return SourceFile{Path: path, Size: size, Checksum: sum}, nil
+}
 
-	bufSize := 1 * MB
-	if size < int64(bufSize) {
-		bufSize = int(size)
+// failureLine Строка для списка FAILED: что упало, почему и что программа написала в stderr.
+func failureLine(what string, res RunResult) string {
+	line := fmt.Sprintf("%s: %v", what, res.Err)
+	if stderr := strings.TrimSpace(res.Stderr); stderr != "" {
+		line += "\n    stderr: " + strings.ReplaceAll(stderr, "\n", "\n            ")
 	}
+	return line
+}
 
-	buf := make([]byte, bufSize)
-	// Используем math/rand, он быстрее crypto/rand для заполнения мусором
-	rand.Read(buf)
+// runSubject Запускает программу iterations раз и возвращает суммарное время копирования
+// и суммарные ресурсы, потраченные на копирование.
+// В холодном режиме перед каждой итерацией исходник и приемник вытесняются из кэша,
+// время на сброс кэша в результат не входит.
+// Первая копия и случайная выборка остальных (VerifySampleRate) сверяются с исходником
+// по размеру и контрольной сумме; при расхождении прогон прерывается с ошибкой.
+// Итерация, не уложившаяся в --timeout, убивается и тоже прерывает прогон.
+func runSubject(prog TestSubject, src SourceFile, dst string, iterations int, mode string) RunResult {
+	var res RunResult
+	sample := newVerifySample(seed)
 
-	written := int64(0)
-	for written < size {
-		toWrite := int64(len(buf))
-		if size-written < toWrite {
-			toWrite = size - written
+	for i := 0; i < iterations; i++ {
+		if mode == CacheCold {
+			if err := evictCache(src.Path, dst); err != nil {
+				fmt.Printf("\nError evicting cache: %v\n", err)
+			}
 		}
-		if _, err := f.Write(buf[:toWrite]); err != nil {
-			return err
+
+		span, u, stderr, err := runIteration(prog, prog.Expand(src.Path, dst))
+		if err != nil {
+			res.Err = fmt.Errorf("iteration %d: %v", i+1, err)
+			res.Stderr = stderr
+			os.Remove(dst)
+			break
+		}
+
+		if sample.Check(i) {
+			if err := verifyCopy(dst, src.Size, src.Checksum); err != nil {
+				res.Err = fmt.Errorf("iteration %d: %v", i+1, err)
+				res.Stderr = stderr
+				os.Remove(dst)
+				break
+			}
+		}
+		res.add(span, u)
+
+		if mode == CacheCold {
+			// Сбрасываем грязные страницы копии, чтобы их запись не попала в следующую итерацию
+			if err := evictCache(dst); err != nil {
+				fmt.Printf("\nError evicting cache: %v\n", err)
+			}
 		}
-		written += toWrite
+		// Удаляем копию сразу
+		os.Remove(dst)
+	}
+
+	return res
+}
+
+// runIteration Один запуск программы с ограничением по времени.
+// Возвращает начало и конец запуска, ресурсы и stderr программы (не больше MaxStderr байт).
+func runIteration(prog TestSubject, args []string) (Span, Usage, string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	stderr := &limitedBuffer{limit: MaxStderr}
+	cmd := exec.CommandContext(ctx, prog.Path, args...)
+	cmd.Stderr = stderr
+	// Если программа оставила потомков, держащих stderr, не ждем их вечно
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	elapsed, u, err := runMeasured(cmd)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %v", timeout)
 	}
-	return nil
+	return Span{Start: start, End: start.Add(elapsed)}, u, stderr.String(), err
 }
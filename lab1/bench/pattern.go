@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+)
+
+// Шаблоны содержимого исходных файлов
+const (
+	PatternRandom    = "random"    // случайные байты по всему файлу, не сжимаются и не дедуплицируются
+	PatternZeros     = "zeros"     // нули, но блоки реально записаны (не дырки)
+	PatternRepeating = "repeating" // один случайный блок 1 МБ, повторенный до нужного размера
+	PatternText      = "text"      // строки из слов, хорошо сжимаются
+	PatternSparse    = "sparse"    // дырки с небольшими островками данных
+)
+
+// SparseIsland Размер островка данных в sparse-файле, SparseStride — шаг между островками.
+const (
+	SparseIsland = 4 * KB
+	SparseStride = 1 * MB
+)
+
+var patterns = []string{PatternRandom, PatternZeros, PatternRepeating, PatternText, PatternSparse}
+
+// words Словарь для шаблона text
+var words = []string{
+	"copy", "file", "buffer", "kernel", "page", "cache", "read", "write",
+	"handle", "system", "call", "block", "disk", "memory", "process", "thread",
+	"the", "a", "of", "and", "to", "in", "is", "for", "on", "with",
+}
+
+// validPattern Проверяет, что шаблон известен.
+func validPattern(name string) bool {
+	for _, p := range patterns {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// createDummyFile Создает файл размера size с содержимым по шаблону pattern.
+// Для одного и того же seed содержимое всегда одинаковое.
+func createDummyFile(filename string, size int64, pattern string, seed int64) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rng := rand.New(rand.NewSource(seed))
+
+	switch pattern {
+	case PatternRandom:
+		err = writeGenerated(f, size, func(buf []byte) { rng.Read(buf) })
+	case PatternZeros:
+		err = writeGenerated(f, size, func(buf []byte) {})
+	case PatternRepeating:
+		err = writeRepeating(f, size, rng)
+	case PatternText:
+		err = writeText(f, size, rng)
+	case PatternSparse:
+		err = writeSparse(f, size, rng)
+	default:
+		err = fmt.Errorf("unknown pattern %q", pattern)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeGenerated Пишет size байт блоками по 1 МБ, каждый блок заполняет fill.
+func writeGenerated(f *os.File, size int64, fill func([]byte)) error {
+	bufSize := 1 * MB
+	if size < int64(bufSize) {
+		bufSize = int(size)
+	}
+	buf := make([]byte, bufSize)
+
+	written := int64(0)
+	for written < size {
+		toWrite := int64(len(buf))
+		if size-written < toWrite {
+			toWrite = size - written
+		}
+		fill(buf[:toWrite])
+		if _, err := f.Write(buf[:toWrite]); err != nil {
+			return err
+		}
+		written += toWrite
+	}
+	return nil
+}
+
+// writeRepeating Один случайный блок повторяется по всему файлу.
+func writeRepeating(f *os.File, size int64, rng *rand.Rand) error {
+	filled := false
+	return writeGenerated(f, size, func(buf []byte) {
+		if !filled {
+			rng.Read(buf)
+			filled = true
+		}
+	})
+}
+
+// writeText Пишет строки из случайных слов словаря.
+func writeText(f *os.File, size int64, rng *rand.Rand) error {
+	w := bufio.NewWriterSize(f, 1*MB)
+	written := int64(0)
+	lineLen := 0
+
+	for written < size {
+		var s string
+		if lineLen > 60 {
+			s = "\n"
+			lineLen = 0
+		} else {
+			s = words[rng.Intn(len(words))] + " "
+			lineLen += len(s)
+		}
+		if int64(len(s)) > size-written {
+			s = s[:size-written]
+		}
+		n, err := w.WriteString(s)
+		if err != nil {
+			return err
+		}
+		written += int64(n)
+	}
+	return w.Flush()
+}
+
+// writeSparse Создает файл с дырками: в начале каждого SparseStride пишется
+// островок случайных данных, остальное остается незаписанным.
+func writeSparse(f *os.File, size int64, rng *rand.Rand) error {
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+
+	buf := make([]byte, SparseIsland)
+	for off := int64(0); off < size; off += SparseStride {
+		n := int64(len(buf))
+		if size-off < n {
+			n = size - off
+		}
+		rng.Read(buf[:n])
+		if _, err := f.WriteAt(buf[:n], off); err != nil {
+			return err
+		}
+	}
+	return nil
+}
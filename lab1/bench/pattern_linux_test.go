@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// allocated Сколько байт файла реально занято на диске.
+func allocated(t *testing.T, path string) int64 {
+	t.Helper()
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		t.Fatal(err)
+	}
+	return st.Blocks * 512
+}
+
+func TestCreateDummyFileSparse(t *testing.T) {
+	dir := t.TempDir()
+	probe := filepath.Join(dir, "probe")
+	if err := os.WriteFile(probe, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(probe, 4*MB); err != nil {
+		t.Fatal(err)
+	}
+	if allocated(t, probe) != 0 {
+		t.Skip("file system does not support holes")
+	}
+
+	const size = 10 * MB
+	path := dummyFile(t, size, PatternSparse, 1)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != size {
+		t.Fatalf("size %d, want %d", info.Size(), size)
+	}
+	// Десять островков по SparseIsland; остальное — дырки
+	if got := allocated(t, path); got >= size/10 {
+		t.Errorf("%d of %d bytes allocated, want holes", got, int64(size))
+	}
+
+	// Для сравнения: нули реально записаны
+	zeros := dummyFile(t, size, PatternZeros, 1)
+	if got := allocated(t, zeros); got < size {
+		t.Errorf("zeros: %d of %d bytes allocated, want no holes", got, int64(size))
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// dummyFile Создает файл по шаблону и возвращает его путь.
+func dummyFile(t *testing.T, size int64, pattern string, seed int64) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), pattern+".bin")
+	if err := createDummyFile(path, size, pattern, seed); err != nil {
+		t.Fatalf("createDummyFile(%s): %v", pattern, err)
+	}
+	return path
+}
+
+func TestCreateDummyFileDeterministic(t *testing.T) {
+	// Больше блока генерации (1 МБ) и не кратно ему
+	const size = 3*MB + 12345
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			sums := map[int64]Checksum{}
+			for _, s := range []int64{1, 1, 2} {
+				path := dummyFile(t, size, pattern, s)
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if info.Size() != size {
+					t.Fatalf("seed %d: size %d, want %d", s, info.Size(), size)
+				}
+				sum, err := fileChecksum(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if prev, ok := sums[s]; ok && prev != sum {
+					t.Errorf("seed %d: content differs between runs", s)
+				}
+				sums[s] = sum
+			}
+			// Нули от зерна не зависят, остальные шаблоны — зависят
+			if same := sums[1] == sums[2]; same != (pattern == PatternZeros) {
+				t.Errorf("seeds 1 and 2 give the same content: %v", same)
+			}
+		})
+	}
+}
+
+func TestCreateDummyFileText(t *testing.T) {
+	for _, size := range []int64{0, 1, 5, 61, 62, 63, 4096, 1*MB + 7} {
+		path := dummyFile(t, size, PatternText, 1)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(data)) != size {
+			t.Errorf("size %d: got %d bytes", size, len(data))
+		}
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			for _, w := range bytes.Fields(line) {
+				if !knownWord(string(w)) && !bytes.HasSuffix(data, w) {
+					t.Fatalf("size %d: unexpected word %q", size, w)
+				}
+			}
+		}
+	}
+}
+
+func knownWord(w string) bool {
+	for _, known := range words {
+		if w == known {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCreateDummyFileRepeating(t *testing.T) {
+	path := dummyFile(t, 2*MB+100, PatternRepeating, 1)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data[:MB], data[MB:2*MB]) || !bytes.Equal(data[:100], data[2*MB:]) {
+		t.Error("blocks do not repeat")
+	}
+	if bytes.Equal(data[:MB/2], data[MB/2:MB]) {
+		t.Error("the block itself is not random")
+	}
+}
+
+func TestCreateDummyFileUnknownPattern(t *testing.T) {
+	if err := createDummyFile(filepath.Join(t.TempDir(), "x"), 10, "bogus", 1); err == nil {
+		t.Error("unknown pattern accepted")
+	}
+}
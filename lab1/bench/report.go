@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// Result Результат одной программы на одном входе в одном режиме кэша.
+type Result struct {
+	Suite      string
+	SizeName   string // имя входа: размер файла или форма дерева
+	Size       int64  // объем данных в байтах
+	Files      int    // число файлов во входе; если задано, скорость считается в файлах/с
+	Program    string
+	Mode       string
+	Iterations int
+	RunResult
+}
+
+// amount Объем одной итерации в единицах Unit.
+func (r Result) amount() float64 {
+	if r.Files > 0 {
+		return float64(r.Files)
+	}
+	return float64(r.Size) / float64(MB)
+}
+
+// x Положение входа на оси X графика.
+func (r Result) x() int64 {
+	if r.Files > 0 {
+		return int64(r.Files)
+	}
+	return r.Size
+}
+
+// Unit Единица измерения скорости.
+func (r Result) Unit() string {
+	if r.Files > 0 {
+		return "files/s"
+	}
+	return "MB/s"
+}
+
+// Throughput Скорость по каждой итерации в единицах Unit.
+func (r Result) Throughput() []float64 {
+	speeds := make([]float64, 0, len(r.Latencies))
+	for _, l := range r.Latencies {
+		if s := l.Seconds(); s > 0 {
+			speeds = append(speeds, r.amount()/s)
+		}
+	}
+	return speeds
+}
+
+// Report Все, что попадает в HTML-отчет.
+type Report struct {
+	Suite       string // значение --suite, попадает в заголовок
+	Started     time.Time
+	Settings    [][2]string // параметры прогона: имя, значение
+	Environment [][2]string // отпечаток окружения, см. environment
+	Results     []Result
+}
+
+// meanStddev Среднее и стандартное отклонение выборки.
+func meanStddev(xs []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	if len(xs) == 1 {
+		return mean, 0
+	}
+	var sq float64
+	for _, x := range xs {
+		sq += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(xs)-1))
+}
+
+// Геометрия графика
+const (
+	chartWidth   = 820
+	chartHeight  = 440
+	chartLeft    = 70
+	chartRight   = 150 // место под легенду
+	chartTop     = 30
+	chartBottom  = 50
+	chartYTicks  = 5
+	chartPadding = 0.1 // запас сверху по оси Y
+)
+
+var chartColors = []string{"#1f77b4", "#d62728", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b"}
+
+type chartPoint struct {
+	X, Y, YLow, YHigh float64
+	Title             string
+}
+
+type chartSeries struct {
+	Name    string
+	Color   string
+	Points  []chartPoint
+	Path    string
+	LegendY float64
+}
+
+type chartTick struct {
+	Pos   float64
+	Label string
+}
+
+type chart struct {
+	Title                    string
+	Unit                     string
+	Width, Height            int
+	Left, Top, Right, Bottom float64
+	LegendX                  float64
+	XTicks, YTicks           []chartTick
+	Series                   []chartSeries
+}
+
+// buildChart Строит график скорости от размера входа (ось X логарифмическая)
+// для одного набора и режима кэша. Точка — среднее по итерациям, планка — ±σ.
+func buildChart(results []Result, suite, mode string) *chart {
+	c := &chart{
+		Title:  fmt.Sprintf("%s: throughput vs input size (%s cache)", suite, mode),
+		Width:  chartWidth,
+		Height: chartHeight,
+		Left:   chartLeft,
+		Top:    chartTop,
+		Right:  chartWidth - chartRight,
+		Bottom: chartHeight - chartBottom,
+	}
+	c.LegendX = c.Right + 14
+
+	type stat struct {
+		r         Result
+		mean, dev float64
+	}
+	byProg := map[string][]stat{}
+	var order []string
+	minSize, maxSize := int64(math.MaxInt64), int64(0)
+	maxY := 0.0
+
+	for _, r := range results {
+		if r.Suite != suite || r.Mode != mode || r.Err != nil || len(r.Latencies) == 0 {
+			continue
+		}
+		c.Unit = r.Unit()
+		mean, dev := meanStddev(r.Throughput())
+		if _, ok := byProg[r.Program]; !ok {
+			order = append(order, r.Program)
+		}
+		byProg[r.Program] = append(byProg[r.Program], stat{r, mean, dev})
+		if r.x() < minSize {
+			minSize = r.x()
+		}
+		if r.x() > maxSize {
+			maxSize = r.x()
+		}
+		if mean+dev > maxY {
+			maxY = mean + dev
+		}
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	logMin, logMax := math.Log10(float64(minSize)), math.Log10(float64(maxSize))
+	if logMax == logMin {
+		logMin, logMax = logMin-0.5, logMax+0.5
+	}
+	maxY *= 1 + chartPadding
+	if maxY == 0 {
+		maxY = 1
+	}
+
+	// Координаты округляем до десятых, чтобы не раздувать SVG
+	xPos := func(size int64) float64 {
+		x := c.Left + (math.Log10(float64(size))-logMin)/(logMax-logMin)*(c.Right-c.Left)
+		return math.Round(x*10) / 10
+	}
+	yPos := func(v float64) float64 {
+		y := c.Bottom - v/maxY*(c.Bottom-c.Top)
+		return math.Round(y*10) / 10
+	}
+
+	ticked := map[int64]bool{}
+	for _, name := range order {
+		for _, st := range byProg[name] {
+			if x := st.r.x(); !ticked[x] {
+				ticked[x] = true
+				c.XTicks = append(c.XTicks, chartTick{xPos(x), st.r.SizeName})
+			}
+		}
+	}
+	for i := 0; i <= chartYTicks; i++ {
+		v := maxY * float64(i) / chartYTicks
+		c.YTicks = append(c.YTicks, chartTick{yPos(v), fmt.Sprintf("%.0f", v)})
+	}
+
+	for i, name := range order {
+		stats := byProg[name]
+		sort.Slice(stats, func(a, b int) bool { return stats[a].r.x() < stats[b].r.x() })
+
+		s := chartSeries{
+			Name:    name,
+			Color:   chartColors[i%len(chartColors)],
+			LegendY: c.Top + 20*float64(i),
+		}
+		for j, st := range stats {
+			p := chartPoint{
+				X:     xPos(st.r.x()),
+				Y:     yPos(st.mean),
+				YLow:  yPos(math.Max(st.mean-st.dev, 0)),
+				YHigh: yPos(st.mean + st.dev),
+				Title: fmt.Sprintf("%s %s: %.2f ± %.2f %s", name, st.r.SizeName, st.mean, st.dev, c.Unit),
+			}
+			s.Points = append(s.Points, p)
+			cmd := "L"
+			if j == 0 {
+				cmd = "M"
+			}
+			s.Path += fmt.Sprintf("%s%.1f,%.1f ", cmd, p.X, p.Y)
+		}
+		c.Series = append(c.Series, s)
+	}
+	return c
+}
+
+type reportRow struct {
+	Result
+	Time  string
+	Speed string
+	Mean  string
+}
+
+// writeHTMLReport Сохраняет самодостаточный HTML-отчет: окружение, графики в SVG и таблицу.
+// Никаких внешних скриптов и стилей, файл открывается в любом браузере офлайн.
+func writeHTMLReport(path string, r *Report) error {
+	var suites, modes []string
+	seen := map[string]bool{}
+	for _, res := range r.Results {
+		if !seen["suite:"+res.Suite] {
+			seen["suite:"+res.Suite] = true
+			suites = append(suites, res.Suite)
+		}
+		if !seen["mode:"+res.Mode] {
+			seen["mode:"+res.Mode] = true
+			modes = append(modes, res.Mode)
+		}
+	}
+
+	var charts []*chart
+	for _, suite := range suites {
+		for _, mode := range modes {
+			if c := buildChart(r.Results, suite, mode); c != nil {
+				charts = append(charts, c)
+			}
+		}
+	}
+
+	rows := make([]reportRow, 0, len(r.Results))
+	for _, res := range r.Results {
+		row := reportRow{Result: res, Time: res.Duration.Round(time.Millisecond).String(), Speed: "FAILED", Mean: "-"}
+		if res.Err == nil {
+			if s := res.Duration.Seconds(); s > 0 {
+				row.Speed = fmt.Sprintf("%.2f %s", res.amount()*float64(res.Completed())/s, res.Unit())
+			}
+			mean, dev := meanStddev(res.Throughput())
+			row.Mean = fmt.Sprintf("%.2f ± %.2f %s", mean, dev, res.Unit())
+		}
+		rows = append(rows, row)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	title := "Benchmark: " + r.Suite
+	if r.Suite == SuiteAll {
+		title = "Benchmark: all suites"
+	}
+
+	err = reportTemplate.Execute(f, map[string]any{
+		"Title":    title,
+		"Started":  r.Started.Format(time.RFC3339),
+		"Settings": r.Settings,
+		"Env":      r.Environment,
+		"Charts":   charts,
+		"Rows":     rows,
+	})
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Started}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f0f0f0; }
+td.num { text-align: right; }
+td.failed { color: #d62728; font-weight: bold; }
+svg { display: block; margin-bottom: 2em; }
+svg text { font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Started {{.Started}}</p>
+
+<h2>Settings</h2>
+<table>
+{{range .Settings}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+
+<h2>Environment</h2>
+<table>
+{{range .Env}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+
+<h2>Charts</h2>
+<p>Points are the mean per-iteration throughput, bars show ±1 standard deviation.</p>
+{{range .Charts}}
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}">
+<text x="{{.Left}}" y="18" font-weight="bold">{{.Title}}</text>
+<line x1="{{.Left}}" y1="{{.Bottom}}" x2="{{.Right}}" y2="{{.Bottom}}" stroke="#000"/>
+<line x1="{{.Left}}" y1="{{.Top}}" x2="{{.Left}}" y2="{{.Bottom}}" stroke="#000"/>
+{{$c := .}}{{range .XTicks}}<line x1="{{.Pos}}" y1="{{$c.Top}}" x2="{{.Pos}}" y2="{{$c.Bottom}}" stroke="#eee"/>
+<text x="{{.Pos}}" y="{{$c.Bottom}}" dy="18" text-anchor="middle">{{.Label}}</text>
+{{end}}{{range .YTicks}}<line x1="{{$c.Left}}" y1="{{.Pos}}" x2="{{$c.Right}}" y2="{{.Pos}}" stroke="#eee"/>
+<text x="{{$c.Left}}" y="{{.Pos}}" dx="-6" dy="4" text-anchor="end">{{.Label}}</text>
+{{end}}<text x="{{.Right}}" y="{{.Bottom}}" dy="38" text-anchor="end">input size (log scale)</text>
+<text x="14" y="{{.Top}}" dy="-10">{{.Unit}}</text>
+{{range .Series}}{{$s := .}}<path d="{{.Path}}" fill="none" stroke="{{.Color}}" stroke-width="2"/>
+{{range .Points}}<line x1="{{.X}}" y1="{{.YLow}}" x2="{{.X}}" y2="{{.YHigh}}" stroke="{{$s.Color}}"/>
+<circle cx="{{.X}}" cy="{{.Y}}" r="4" fill="{{$s.Color}}"><title>{{.Title}}</title></circle>
+{{end}}<rect x="{{$c.LegendX}}" y="{{.LegendY}}" width="12" height="12" fill="{{.Color}}"/>
+<text x="{{$c.LegendX}}" y="{{.LegendY}}" dx="18" dy="11">{{.Name}}</text>
+{{end}}</svg>
+{{else}}<p>No successful runs to plot.</p>
+{{end}}
+
+<h2>Results</h2>
+<table>
+<tr><th>Suite</th><th>Input</th><th>Program</th><th>Cache</th><th>Iterations</th><th>Time total</th><th>Speed</th><th>Per iteration</th></tr>
+{{range .Rows}}<tr><td>{{.Suite}}</td><td>{{.SizeName}}</td><td>{{.Program}}</td><td>{{.Mode}}</td><td class="num">{{.Completed}}/{{.Iterations}}</td><td class="num">{{.Time}}</td>
+{{if .Err}}<td class="failed" colspan="2">FAILED: {{.Err}}{{if .Stderr}}<pre>{{.Stderr}}</pre>{{end}}</td>{{else}}<td class="num">{{.Speed}}</td><td class="num">{{.Mean}}</td>{{end}}</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// chartResult Успешный результат горячего прогона копирования с итерациями
+// заданной длительности.
+func chartResult(prog string, size int64, latencies ...time.Duration) Result {
+	return Result{
+		Suite: SuiteCopy, SizeName: formatBytes(size), Size: size, Program: prog, Mode: CacheHot,
+		RunResult: RunResult{Latencies: latencies},
+	}
+}
+
+func TestBuildChart(t *testing.T) {
+	ms := time.Millisecond
+	failed := chartResult("cpW", MB, 10*ms)
+	failed.Err = errors.New("checksum mismatch")
+	cold := chartResult("cpC", MB, 10*ms)
+	cold.Mode = CacheCold
+
+	tests := []struct {
+		name    string
+		results []Result
+		check   func(t *testing.T, c *chart)
+	}{
+		{"no results", nil, func(t *testing.T, c *chart) {
+			if c != nil {
+				t.Error("chart built without results")
+			}
+		}},
+		{"only failed and other mode", []Result{failed, cold}, func(t *testing.T, c *chart) {
+			if c != nil {
+				t.Error("chart built from failed and cold results")
+			}
+		}},
+		{"single x value", []Result{chartResult("cpC", MB, 10*ms, 10*ms)}, func(t *testing.T, c *chart) {
+			p := c.Series[0].Points[0]
+			// Одна точка — посередине оси X
+			if mid := math.Round((c.Left+c.Right)/2*10) / 10; p.X != mid {
+				t.Errorf("x = %v, want %v", p.X, mid)
+			}
+			if len(c.XTicks) != 1 || p.YLow != p.Y || p.YHigh != p.Y {
+				t.Errorf("ticks %v, error bar %v..%v at %v", c.XTicks, p.YLow, p.YHigh, p.Y)
+			}
+		}},
+		{"lower error bar clamped at 0", []Result{chartResult("cpC", MB, 10*ms, time.Second, time.Second)}, func(t *testing.T, c *chart) {
+			// 100, 1 и 1 МБ/с: σ (~57) больше среднего (34)
+			p := c.Series[0].Points[0]
+			if p.YLow != c.Bottom {
+				t.Errorf("lower bar at %v, want the axis at %v", p.YLow, c.Bottom)
+			}
+			if p.YHigh < c.Top {
+				t.Errorf("upper bar at %v is above the plot top %v", p.YHigh, c.Top)
+			}
+		}},
+		{"failed results skipped", []Result{chartResult("cpC", MB, 10*ms), failed, chartResult("cpC", 10*MB, 100*ms), cold}, func(t *testing.T, c *chart) {
+			if len(c.Series) != 1 || c.Series[0].Name != "cpC" {
+				t.Fatalf("series %+v, want only cpC", c.Series)
+			}
+			pts := c.Series[0].Points
+			if len(pts) != 2 || pts[0].X != c.Left || pts[1].X != c.Right {
+				t.Errorf("points %+v, want two at the ends of the axis", pts)
+			}
+		}},
+		{"points sorted by size", []Result{chartResult("cpC", 10*MB, 100*ms), chartResult("cpC", MB, 10*ms)}, func(t *testing.T, c *chart) {
+			pts := c.Series[0].Points
+			if pts[0].X > pts[1].X {
+				t.Errorf("points not sorted by x: %v, %v", pts[0].X, pts[1].X)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, buildChart(tt.results, SuiteCopy, CacheHot))
+		})
+	}
+}
+
+func TestMeanStddev(t *testing.T) {
+	tests := []struct {
+		xs        []float64
+		mean, dev float64
+	}{
+		{nil, 0, 0},
+		{[]float64{5}, 5, 0},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, math.Sqrt(32.0 / 7)},
+	}
+	for _, tt := range tests {
+		mean, dev := meanStddev(tt.xs)
+		if math.Abs(mean-tt.mean) > 1e-9 || math.Abs(dev-tt.dev) > 1e-9 {
+			t.Errorf("meanStddev(%v) = %v, %v; want %v, %v", tt.xs, mean, dev, tt.mean, tt.dev)
+		}
+	}
+}
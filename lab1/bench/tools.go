@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// Наборы тестов
+const (
+	SuiteCopy = "copy" // копировщики lab1
+	SuiteCat  = "cat"  // cat из lab4, вывод в /dev/null
+	SuiteLs   = "ls"   // ls_util из lab8 на сгенерированных деревьях
+	SuiteRm   = "rm"   // rm_util из lab8, удаление деревьев
+	SuiteAll  = "all"
+)
+
+// ToolIterations Сколько раз запускаются ls и rm на каждом дереве
+const ToolIterations = 20
+
+// ToolInput Входные данные для утилиты.
+type ToolInput struct {
+	Name  string
+	Size  int64 // объем данных в байтах, скорость в МБ/с
+	Files int   // число файлов, скорость в файлах/с (если задано)
+
+	// Create Создает вход по пути path.
+	Create func(path string) error
+	// Consumed Программа уничтожает вход, перед каждой итерацией он создается заново
+	Consumed bool
+}
+
+// ToolSuite Утилита и набор входов для нее.
+type ToolSuite struct {
+	Name    string
+	Subject TestSubject
+	Inputs  []ToolInput
+}
+
+// Unit Единица измерения скорости.
+func (in ToolInput) Unit() string {
+	if in.Files > 0 {
+		return "files/s"
+	}
+	return "MB/s"
+}
+
+// TreeShape Форма сгенерированного дерева каталогов.
+type TreeShape struct {
+	Name   string
+	Depth  int // число уровней подкаталогов
+	Fanout int // подкаталогов в каждом каталоге
+	Files  int // файлов в каждом каталоге
+}
+
+// TotalFiles Сколько всего файлов в дереве.
+func (t TreeShape) TotalFiles() int {
+	dirs, level := 0, 1
+	for d := 0; d <= t.Depth; d++ {
+		dirs += level
+		level *= t.Fanout
+	}
+	return dirs * t.Files
+}
+
+// createTree Создает дерево формы shape в каталоге root. Файлы маленькие,
+// важна только их численность.
+func createTree(root string, shape TreeShape) error {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	for i := 0; i < shape.Files; i++ {
+		name := filepath.Join(root, fmt.Sprintf("file%04d.txt", i))
+		if err := os.WriteFile(name, []byte(name), 0644); err != nil {
+			return err
+		}
+	}
+	if shape.Depth == 0 {
+		return nil
+	}
+	sub := shape
+	sub.Depth--
+	for i := 0; i < shape.Fanout; i++ {
+		if err := createTree(filepath.Join(root, fmt.Sprintf("dir%02d", i)), sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// treeInputs Превращает формы деревьев во входы утилиты.
+func treeInputs(shapes []TreeShape, consumed bool) []ToolInput {
+	inputs := make([]ToolInput, 0, len(shapes))
+	for _, shape := range shapes {
+		shape := shape
+		inputs = append(inputs, ToolInput{
+			Name:     shape.Name,
+			Files:    shape.TotalFiles(),
+			Create:   func(path string) error { return createTree(path, shape) },
+			Consumed: consumed,
+		})
+	}
+	return inputs
+}
+
+// fileInputs Входы из файлов всех размеров fileSizes, по текущему --pattern.
+func fileInputs() []ToolInput {
+	inputs := make([]ToolInput, 0, len(fileSizes))
+	for _, fs := range fileSizes {
+		size := fs.size
+		inputs = append(inputs, ToolInput{
+			Name:   fs.name,
+			Size:   size,
+			Create: func(path string) error { return createDummyFile(path, size, patternName, seed) },
+		})
+	}
+	return inputs
+}
+
+// ПУТИ (относительно папки bench/)
+var toolSuites = []ToolSuite{
+	{
+		Name:    SuiteCat,
+		Subject: TestSubject{Name: "cat", Path: "../../lab4/lab4.exe", Args: []string{"{src}"}},
+		Inputs:  fileInputs(),
+	},
+	{
+		Name:    SuiteLs,
+		Subject: TestSubject{Name: "ls_util", Path: "../../lab8/ls_util/ls_util.exe", Args: []string{"-R", "{src}/*"}},
+		Inputs: treeInputs([]TreeShape{
+			{Name: "100 files", Depth: 0, Fanout: 0, Files: 100},
+			{Name: "1100 files", Depth: 1, Fanout: 10, Files: 100},
+			{Name: "11100 files", Depth: 2, Fanout: 10, Files: 100},
+		}, false),
+	},
+	{
+		Name:    SuiteRm,
+		Subject: TestSubject{Name: "rm_util", Path: "../../lab8/rm_util/rm_util.exe", Args: []string{"-r", "{src}"}},
+		Inputs: treeInputs([]TreeShape{
+			{Name: "flat 1000", Depth: 0, Fanout: 0, Files: 1000},
+			{Name: "wide 10x10", Depth: 2, Fanout: 10, Files: 10},
+			{Name: "deep 50", Depth: 50, Fanout: 1, Files: 2},
+		}, true),
+	},
+}
+
+// selectSuites Разбирает значение --suite.
+func selectSuites(name string) (map[string]bool, error) {
+	all := []string{SuiteCopy}
+	for _, s := range toolSuites {
+		all = append(all, s.Name)
+	}
+
+	selected := map[string]bool{}
+	for _, s := range all {
+		if name == SuiteAll || name == s {
+			selected[s] = true
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("unknown suite %q (want copy, cat, ls, rm or all)", name)
+	}
+	return selected, nil
+}
+
+// selectedSubjects Программы выбранных наборов. Указатели позволяют
+// заменить пути на абсолютные на месте.
+func selectedSubjects(suites map[string]bool) []*TestSubject {
+	var subjects []*TestSubject
+	if suites[SuiteCopy] {
+		for i := range programs {
+			subjects = append(subjects, &programs[i])
+		}
+	}
+	for i := range toolSuites {
+		if suites[toolSuites[i].Name] {
+			subjects = append(subjects, &toolSuites[i].Subject)
+		}
+	}
+	return subjects
+}
+
+// toolIterations Для файловых входов — как у копировщиков, для деревьев — ToolIterations.
+func toolIterations(in ToolInput) int {
+	if in.Files > 0 {
+		return ToolIterations
+	}
+	if in.Size <= SmallFileLimit {
+		return IterationsSmall
+	}
+	if n := int(TargetVolumeLarge / in.Size); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// runToolSuite Прогоняет утилиту на всех ее входах и печатает таблицу.
+// Возвращает true, если прогон остановлен из-за --fail-fast.
+func runToolSuite(suite ToolSuite, report *Report, modes []string, failures *[]string) bool {
+	w := tabwriter.NewWriter(os.Stdout, 10, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "SUITE: %s\n", suite.Name)
+	// Холодный режим для деревьев возможен, только если drop_caches доступен
+	var treeCold error
+	if slices.Contains(modes, CacheCold) {
+		if treeCold = dropAllCaches(); treeCold != nil {
+			fmt.Fprintf(w, "(cold runs of directory trees skipped: %v)\n", treeCold)
+		}
+	}
+	fmt.Fprintln(w, "INPUT\tPROGRAM\tCACHE\tITERS\tTIME TOTAL\tSPEED\t"+usageHeader)
+	fmt.Fprintln(w, "-----\t-------\t-----\t-----\t----------\t-----\t"+usageRule)
+	defer w.Flush()
+
+	for _, in := range suite.Inputs {
+		iterations := toolIterations(in)
+		path := filepath.Join(srcDir, fmt.Sprintf("bench_%s_input", suite.Name))
+
+		for _, mode := range modes {
+			if mode == CacheCold && in.Files > 0 && treeCold != nil {
+				// Без сброса dentry/inode такой прогон был бы горячим под чужой меткой
+				continue
+			}
+			time.Sleep(100 * time.Millisecond)
+
+			res := runToolInput(suite, in, path, iterations, mode)
+			report.Results = append(report.Results, Result{
+				Suite:      suite.Name,
+				SizeName:   in.Name,
+				Size:       in.Size,
+				Files:      in.Files,
+				Program:    suite.Subject.Name,
+				Mode:       mode,
+				Iterations: iterations,
+				RunResult:  res,
+			})
+
+			completed := res.Completed()
+			units := float64(in.Size) * float64(completed) / float64(MB)
+			if in.Files > 0 {
+				units = float64(in.Files) * float64(completed)
+			}
+			speed := 0.0
+			if s := res.Duration.Seconds(); s > 0.0001 {
+				speed = units / s
+			}
+			speedCol := fmt.Sprintf("%.2f %s", speed, in.Unit())
+			if res.Err != nil {
+				speedCol = "FAILED"
+				*failures = append(*failures, failureLine(fmt.Sprintf("%s %s (%s)", suite.Name, in.Name, mode), res))
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%v\t%s\t%s\n",
+				in.Name,
+				suite.Subject.Name,
+				mode,
+				completed,
+				iterations,
+				res.Duration.Round(time.Millisecond),
+				speedCol,
+				usageColumns(res.Usage.PerIteration(completed)),
+			)
+			if res.Err != nil && failFast {
+				return true
+			}
+		}
+	}
+	fmt.Fprintln(w)
+	return false
+}
+
+// runToolInput Запускает утилиту iterations раз на одном входе.
+// Подготовка входа в замер времени не входит. Для rm проверяется, что дерево
+// действительно удалено, иначе "бесплатный" rm выиграл бы любой замер.
+func runToolInput(suite ToolSuite, in ToolInput, path string, iterations int, mode string) RunResult {
+	var res RunResult
+	defer os.RemoveAll(path)
+
+	for i := 0; i < iterations; i++ {
+		if i == 0 || in.Consumed {
+			os.RemoveAll(path)
+			if err := in.Create(path); err != nil {
+				res.Err = fmt.Errorf("cannot create input: %v", err)
+				return res
+			}
+		}
+		if mode == CacheCold {
+			// Для деревьев fadvise бессмыслен: нужен сброс dentry/inode под root
+			evict := func() error { return evictCache(path) }
+			if in.Files > 0 {
+				evict = dropAllCaches
+			}
+			if err := evict(); err != nil {
+				res.Err = fmt.Errorf("iteration %d: cannot evict cache: %v", i+1, err)
+				return res
+			}
+		}
+
+		span, u, stderr, err := runIteration(suite.Subject, suite.Subject.Expand(path, os.DevNull))
+		if err != nil {
+			res.Err = fmt.Errorf("iteration %d: %v", i+1, err)
+			res.Stderr = stderr
+			return res
+		}
+		if in.Consumed {
+			if _, err := os.Lstat(path); !os.IsNotExist(err) {
+				res.Err = fmt.Errorf("iteration %d: input still exists after run", i+1)
+				res.Stderr = stderr
+				return res
+			}
+		}
+
+		res.add(span, u)
+	}
+	return res
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Usage Ресурсы, потраченные дочерним процессом (или суммой процессов).
+// Поля, которые платформа не умеет собирать, остаются нулевыми.
+type Usage struct {
+	User time.Duration // пользовательское время CPU
+	Sys  time.Duration // системное время CPU
+
+	MaxRSS int64 // пиковый RSS в KB
+
+	MinFlt int64 // минорные page faults
+	MajFlt int64 // мажорные page faults
+
+	NVCsw  int64 // добровольные переключения контекста
+	NIvCsw int64 // принудительные переключения контекста
+
+	// Из /proc/<pid>/io
+	SyscR      int64 // число read-подобных syscall
+	SyscW      int64 // число write-подобных syscall
+	ReadBytes  int64 // байт реально прочитано с носителя
+	WriteBytes int64 // байт реально отправлено на носитель
+}
+
+// Add Добавляет к сумме результат одного запуска. MaxRSS берется максимальный.
+func (u *Usage) Add(o Usage) {
+	u.User += o.User
+	u.Sys += o.Sys
+	if o.MaxRSS > u.MaxRSS {
+		u.MaxRSS = o.MaxRSS
+	}
+	u.MinFlt += o.MinFlt
+	u.MajFlt += o.MajFlt
+	u.NVCsw += o.NVCsw
+	u.NIvCsw += o.NIvCsw
+	u.SyscR += o.SyscR
+	u.SyscW += o.SyscW
+	u.ReadBytes += o.ReadBytes
+	u.WriteBytes += o.WriteBytes
+}
+
+// PerIteration Усредняет накопленные значения на n запусков. MaxRSS не усредняется.
+func (u Usage) PerIteration(n int) Usage {
+	if n <= 1 {
+		return u
+	}
+	d := int64(n)
+	return Usage{
+		User:       u.User / time.Duration(n),
+		Sys:        u.Sys / time.Duration(n),
+		MaxRSS:     u.MaxRSS,
+		MinFlt:     u.MinFlt / d,
+		MajFlt:     u.MajFlt / d,
+		NVCsw:      u.NVCsw / d,
+		NIvCsw:     u.NIvCsw / d,
+		SyscR:      u.SyscR / d,
+		SyscW:      u.SyscW / d,
+		ReadBytes:  u.ReadBytes / d,
+		WriteBytes: u.WriteBytes / d,
+	}
+}
+
+// usageHeader Заголовки колонок для tabwriter, порядок совпадает с usageColumns.
+const usageHeader = "USER\tSYS\tMAXRSS\tFLT min/maj\tCSW vol/inv\tSYSC r/w\tIO r/w"
+
+// usageRule Разделитель под usageHeader.
+const usageRule = "----\t---\t------\t-----------\t-----------\t--------\t------"
+
+// usageColumns Форматирует ресурсы для строки таблицы (значения на одну итерацию).
+func usageColumns(u Usage) string {
+	return fmt.Sprintf("%v\t%v\t%d KB\t%d/%d\t%d/%d\t%d/%d\t%s/%s",
+		u.User.Round(time.Microsecond),
+		u.Sys.Round(time.Microsecond),
+		u.MaxRSS,
+		u.MinFlt, u.MajFlt,
+		u.NVCsw, u.NIvCsw,
+		u.SyscR, u.SyscW,
+		formatBytes(u.ReadBytes), formatBytes(u.WriteBytes),
+	)
+}
+
+// formatBytes Переводит байты в удобочитаемый вид.
+func formatBytes(n int64) string {
+	switch {
+	case n >= GB:
+		return fmt.Sprintf("%.1fG", float64(n)/GB)
+	case n >= MB:
+		return fmt.Sprintf("%.1fM", float64(n)/MB)
+	case n >= KB:
+		return fmt.Sprintf("%.1fK", float64(n)/KB)
+	}
+	return fmt.Sprintf("%d", n)
+}
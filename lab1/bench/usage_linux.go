@@ -0,0 +1,87 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// runMeasured Запускает команду, замеряет время выполнения и собирает Usage.
+// Процесс ожидается через waitid(WNOWAIT): зомби еще не забран, поэтому
+// /proc/<pid>/io можно прочитать уже после завершения. Затем cmd.Wait забирает
+// процесс и отдает rusage.
+func runMeasured(cmd *exec.Cmd) (time.Duration, Usage, error) {
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return 0, Usage{}, err
+	}
+	pid := cmd.Process.Pid
+
+	var info unix.Siginfo
+	for {
+		err := unix.Waitid(unix.P_PID, pid, &info, unix.WEXITED|unix.WNOWAIT, nil)
+		if err != unix.EINTR {
+			break
+		}
+	}
+	elapsed := time.Since(start)
+
+	var u Usage
+	// Ошибку игнорируем: без прав на /proc просто не будет колонок I/O
+	readProcIO(pid, &u)
+
+	err := cmd.Wait()
+	if cmd.ProcessState != nil {
+		u.User = cmd.ProcessState.UserTime()
+		u.Sys = cmd.ProcessState.SystemTime()
+		if ru, ok := cmd.ProcessState.SysUsage().(*syscall.Rusage); ok {
+			u.MaxRSS = ru.Maxrss // на Linux уже в KB
+			u.MinFlt = ru.Minflt
+			u.MajFlt = ru.Majflt
+			u.NVCsw = ru.Nvcsw
+			u.NIvCsw = ru.Nivcsw
+		}
+	}
+	return elapsed, u, err
+}
+
+// readProcIO Читает счетчики ввода-вывода процесса из /proc/<pid>/io.
+func readProcIO(pid int, u *Usage) error {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/io", pid))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "syscr":
+			u.SyscR = n
+		case "syscw":
+			u.SyscW = n
+		case "read_bytes":
+			u.ReadBytes = n
+		case "write_bytes":
+			u.WriteBytes = n
+		}
+	}
+	return scanner.Err()
+}
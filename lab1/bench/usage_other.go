@@ -0,0 +1,23 @@
+//go:build !linux
+
+package main
+
+import (
+	"os/exec"
+	"time"
+)
+
+// runMeasured Запускает команду и замеряет время. Кроме времени CPU
+// остальные счетчики на этой платформе не собираются.
+func runMeasured(cmd *exec.Cmd) (time.Duration, Usage, error) {
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start)
+
+	var u Usage
+	if cmd.ProcessState != nil {
+		u.User = cmd.ProcessState.UserTime()
+		u.Sys = cmd.ProcessState.SystemTime()
+	}
+	return elapsed, u, err
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+)
+
+// VerifySampleRate Доля итераций (кроме первой, она проверяется всегда),
+// после которых проверяется результат копирования.
+const VerifySampleRate = 0.1
+
+// verifySample Выборка итераций для проверки: первая всегда, остальные с
+// вероятностью VerifySampleRate. Выборка детерминирована, чтобы повторные
+// прогоны с тем же зерном проверяли те же итерации.
+type verifySample struct {
+	rng *rand.Rand
+}
+
+func newVerifySample(seed int64) *verifySample {
+	return &verifySample{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Check Проверять ли итерацию i (с 0). Итерации спрашиваются по порядку.
+func (s *verifySample) Check(i int) bool {
+	return i == 0 || s.rng.Float64() < VerifySampleRate
+}
+
+// Checksum Контрольная сумма файла.
+type Checksum [sha256.Size]byte
+
+// fileChecksum Считает SHA-256 содержимого файла.
+func fileChecksum(path string) (Checksum, error) {
+	var sum Checksum
+
+	f, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// verifyCopy Проверяет, что копия имеет ожидаемые размер и контрольную сумму.
+func verifyCopy(dst string, size int64, want Checksum) error {
+	info, err := os.Stat(dst)
+	if err != nil {
+		return fmt.Errorf("destination missing: %v", err)
+	}
+	if info.Size() != size {
+		return fmt.Errorf("size mismatch: got %d bytes, want %d", info.Size(), size)
+	}
+
+	got, err := fileChecksum(dst)
+	if err != nil {
+		return fmt.Errorf("cannot read destination: %v", err)
+	}
+	if got != want {
+		return fmt.Errorf("checksum mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package console
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// AnswersEnv Переменная окружения с ответами на вопросы, по одному на строку.
+const AnswersEnv = "LAB2_ANSWERS"
+
+// answerScript Заранее заданные ответы. Пока он установлен, все запросы
+// (ConsolePrompt, PromptLine, ReadPassword, LineEditor, виджеты UI) берут
+// ответы из него, а не из консоли, и печатают их после приглашения, чтобы
+// в захваченном выводе было видно, что было «введено». Пароли печатаются маской.
+type answerScript struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+}
+
+var script *answerScript
+
+// SetAnswers Включает сценарный режим: ответы — строки из r (\r\n или \n).
+// Когда ответы кончаются, запросы возвращают io.EOF.
+func SetAnswers(r io.Reader) error {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 4096), DefaultMaxLine)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimSuffix(sc.Text(), "\r"))
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	script = &answerScript{lines: lines}
+	return nil
+}
+
+// LoadAnswers Включает сценарный режим по аргументу --answers: путь к файлу
+// или "-" — стандартный ввод (here-doc). Пустой аргумент — ответы из
+// переменной AnswersEnv, если она задана; иначе режим остается интерактивным.
+func LoadAnswers(source string) error {
+	switch source {
+	case "":
+		if env, ok := os.LookupEnv(AnswersEnv); ok {
+			return SetAnswers(strings.NewReader(env))
+		}
+		return nil
+	case "-":
+		return SetAnswers(os.Stdin)
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return SetAnswers(f)
+}
+
+// scriptedAnswer Если включен сценарный режим, выводит приглашение, берет
+// следующий ответ и печатает его (для секретов — mask на каждый символ).
+// ok == false — режим выключен, нужно читать консоль.
+func scriptedAnswer(hOut Handle, prompt string, secret bool, mask rune) (answer string, ok bool, err error) {
+	s := script
+	if s == nil {
+		return "", false, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := NewConsole(hOut)
+	if _, err := out.WriteString(prompt); err != nil {
+		return "", true, err
+	}
+	if s.next >= len(s.lines) {
+		out.WriteString("\r\n")
+		return "", true, io.EOF
+	}
+	answer = s.lines[s.next]
+	s.next++
+
+	shown := answer
+	if secret {
+		shown = ""
+		if mask != 0 {
+			shown = strings.Repeat(string(mask), utf8.RuneCountInString(answer))
+		}
+	}
+	_, err = out.WriteString(shown + "\r\n")
+	return answer, true, err
+}
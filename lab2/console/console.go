@@ -0,0 +1,153 @@
+// Package console Консольный ввод-вывод для Windows и Linux: приглашения с эхом
+// и без, пароли, построчный редактор, оформление текста и виджеты. Пакет
+// общий для лабораторных; демонстрация — в lab2/main.go.
+package console
+
+import (
+	"errors"
+	"io"
+)
+
+// Платформенная часть (console_windows.go, console_linux.go) предоставляет:
+//   Handle                      — дескриптор консоли (HANDLE в Windows, fd в Linux)
+//   GetStdHandle(n)             — стандартные дескрипторы STD_INPUT_HANDLE/STD_OUTPUT_HANDLE
+//   writeFile/readFile          — один вызов WriteFile/ReadFile или write(2)/read(2)
+//   retryWrite                  — можно ли повторить запись после ошибки
+//   consoleMode                 — режим консоли (флаги в Windows, termios в Linux)
+//   getConsoleMode/setConsoleMode, disableEcho
+//   enterMode                   — смена режима с восстановлением по сигналам (modeGuard)
+
+// ErrInterrupted Ввод прерван сигналом (Ctrl+C, SIGTERM). Режим консоли к этому
+// моменту уже восстановлен. Проверяется через errors.Is.
+var ErrInterrupted = errors.New("console input interrupted")
+
+// PrintMsg Выводит одну строку в дескриптор. Строка пишется целиком (см. Console);
+// false — если записать все не удалось.
+func PrintMsg(hOut Handle, msg string) bool {
+	_, err := NewConsole(hOut).WriteString(msg)
+	return err == nil
+}
+
+// PrintStrings Выводит список строк переменной длины.
+func PrintStrings(hOut Handle, msg ...string) bool {
+	c := NewConsole(hOut)
+	for _, m := range msg {
+		if _, err := c.WriteString(m); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ConsolePrompt Выводит приглашение и читает ответ. Не поместившийся в буфер
+// остаток строки отбрасывается; строки любой длины читает PromptLine.
+func ConsolePrompt(pPromptMsg string, pResponse []byte, echo bool) (int, bool) {
+	n, err := Prompt(pPromptMsg, pResponse, echo)
+	return n, err == nil
+}
+
+// Prompt То же, что ConsolePrompt, но с ошибкой вместо false.
+// Прерывание ввода сигналом возвращается как ErrInterrupted.
+func Prompt(pPromptMsg string, pResponse []byte, echo bool) (int, error) {
+	hOut, _ := GetStdHandle(STD_OUTPUT_HANDLE)
+	hIn, _ := GetStdHandle(STD_INPUT_HANDLE)
+	return consolePrompt(hIn, hOut, pPromptMsg, pResponse, echo)
+}
+
+// consolePrompt То же, что Prompt, но для произвольных дескрипторов.
+// Если ввод не консоль (файл, канал), режим эха не трогаем и читаем одну строку
+// побайтно, чтобы не забрать из канала ответы на следующие вопросы.
+func consolePrompt(hIn, hOut Handle, pPromptMsg string, pResponse []byte, echo bool) (int, error) {
+	if answer, ok, err := scriptedAnswer(hOut, pPromptMsg, !echo, 0); ok {
+		if err != nil {
+			return 0, err
+		}
+		return fitLine(pResponse, copy(pResponse, answer)), nil
+	}
+
+	if _, err := NewConsole(hOut).WriteString(pPromptMsg); err != nil {
+		return 0, err
+	}
+
+	// настройка режима эха
+	originalMode, err := getConsoleMode(hIn)
+	if err != nil {
+		// не консоль — просто читаем строку
+		read, err := readLine(hIn, pResponse)
+		if err != nil {
+			return 0, err
+		}
+		if truncated(pResponse, read) {
+			drainLine(func(b []byte) (int, error) { return readFile(hIn, b) }, 1)
+		}
+		return trimNewline(pResponse, fitLine(pResponse, read)), nil
+	}
+
+	var read int
+	if echo {
+		// Режим не меняем, восстанавливать нечего
+		read, err = readFile(hIn, pResponse)
+		if err == nil && truncated(pResponse, read) {
+			// Строка не влезла: остаток не должен попасть в следующий запрос
+			drainLine(func(b []byte) (int, error) { return readFile(hIn, b) }, 256)
+		}
+	} else {
+		// Отключаем эхо; при Ctrl+C, SIGTERM и SIGTSTP режим вернется сам
+		guard, gerr := enterMode(hIn, originalMode, disableEcho(originalMode))
+		if gerr != nil {
+			return 0, gerr
+		}
+		read, err = guard.Read(pResponse)
+		if err == nil && truncated(pResponse, read) {
+			drainLine(guard.Read, 256)
+		}
+
+		// Восстанавливаем режим сразу после чтения
+		guard.Restore()
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return trimNewline(pResponse, fitLine(pResponse, read)), nil
+}
+
+// truncated Буфер заполнен, а перевода строки в нем нет — строка длиннее буфера.
+func truncated(buf []byte, count int) bool {
+	return count > 0 && count == len(buf) && buf[count-1] != '\n'
+}
+
+// readLine Читает по одному байту до '\n' включительно или до заполнения буфера.
+// Конец файла без единого прочитанного байта — ошибка io.EOF.
+func readLine(h Handle, buf []byte) (int, error) {
+	count := 0
+	for count < len(buf) {
+		n, err := readFile(h, buf[count:count+1])
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			if count == 0 {
+				return 0, io.EOF
+			}
+			break
+		}
+		count++
+		if buf[count-1] == '\n' {
+			break
+		}
+	}
+	return count, nil
+}
+
+// trimNewline Убирает символы перевода строки (\r\n) из конца, если они есть.
+func trimNewline(buf []byte, count int) int {
+	if count > 0 && buf[count-1] == '\n' {
+		count--
+	}
+	if count > 0 && buf[count-1] == '\r' {
+		count--
+	}
+	return count
+}
@@ -0,0 +1,113 @@
+//go:build linux
+
+package console
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// В Linux стандартные дескрипторы — просто номера fd
+const (
+	STD_INPUT_HANDLE  = 0
+	STD_OUTPUT_HANDLE = 1
+)
+
+// Handle Файловый дескриптор.
+type Handle = int
+
+// consoleMode Настройки терминала (termios).
+type consoleMode = syscall.Termios
+
+// GetStdHandle Возвращает стандартный дескриптор.
+func GetStdHandle(n int) (Handle, error) {
+	return Handle(n), nil
+}
+
+// writeFile Один вызов write(2).
+func writeFile(h Handle, b []byte) (int, error) {
+	n, err := syscall.Write(h, b)
+	if n < 0 {
+		n = 0
+	}
+	return n, err
+}
+
+// retryWrite EINTR — просто повторить; EAGAIN (неблокирующий fd, буфер
+// терминала или канала полон) — дождаться готовности fd к записи и повторить.
+func retryWrite(h Handle, err error) bool {
+	switch err {
+	case syscall.EINTR:
+		return true
+	case syscall.EAGAIN:
+		// poll, а не select: FdSet вмещает только fd < 1024
+		fds := []unix.PollFd{{Fd: int32(h), Events: unix.POLLOUT}}
+		for {
+			_, err := unix.Poll(fds, -1)
+			if err != unix.EINTR {
+				return err == nil
+			}
+		}
+	}
+	return false
+}
+
+// readFile Один вызов read(2).
+func readFile(h Handle, b []byte) (int, error) {
+	n, err := syscall.Read(h, b)
+	if n < 0 {
+		n = 0
+	}
+	return n, err
+}
+
+// getConsoleMode Аналог GetConsoleMode: tcgetattr через ioctl(TCGETS).
+// Для fd, не связанного с терминалом, возвращает ENOTTY.
+func getConsoleMode(h Handle) (consoleMode, error) {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(h), syscall.TCGETS, uintptr(unsafe.Pointer(&t)))
+	if errno != 0 {
+		return t, errno
+	}
+	return t, nil
+}
+
+// setConsoleMode Аналог SetConsoleMode: tcsetattr(TCSANOW) через ioctl(TCSETS).
+func setConsoleMode(h Handle, mode consoleMode) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(h), syscall.TCSETS, uintptr(unsafe.Pointer(&mode)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// disableEcho Сбрасывает ECHO. ECHONL оставляем как есть: перевод строки
+// после пароля, как и в Windows, не печатается.
+func disableEcho(mode consoleMode) consoleMode {
+	mode.Lflag &^= syscall.ECHO
+	return mode
+}
+
+// makeRaw Режим для построчного редактора: без канонического ввода и эха,
+// байты приходят по одному. ISIG оставляем, чтобы Ctrl+C и Ctrl+Z работали
+// через сигналы (их обрабатывает modeGuard), OPOST — чтобы \n выводился как \r\n.
+func makeRaw(mode consoleMode) consoleMode {
+	mode.Iflag &^= syscall.ICRNL | syscall.INLCR | syscall.IGNCR | syscall.IXON | syscall.ISTRIP
+	mode.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ECHONL | syscall.IEXTEN
+	mode.Cc[syscall.VMIN] = 1
+	mode.Cc[syscall.VTIME] = 0
+	return mode
+}
+
+// enableVirtualTerminal Терминалы Linux и так понимают ANSI-последовательности.
+func enableVirtualTerminal(h Handle) func() {
+	return func() {}
+}
+
+// supportsANSI Вывод — терминал. Терминалы Linux понимают ANSI-последовательности.
+func supportsANSI(h Handle) bool {
+	_, err := getConsoleMode(h)
+	return err == nil
+}
@@ -0,0 +1,612 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// openPTY Открывает пару псевдотерминалов: master для теста, slave для consolePrompt.
+func openPTY(t *testing.T) (*os.File, *os.File) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminals: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	// unlockpt и ptsname. master.Fd() не используем: он переводит файл
+	// в блокирующий режим, и дедлайны чтения перестают работать
+	conn, err := master.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n uint32
+	var errno syscall.Errno
+	conn.Control(func(fd uintptr) {
+		var unlock int32
+		if _, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+			return
+		}
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n)))
+	})
+	if errno != 0 {
+		t.Fatalf("unlockpt/ptsname: %v", errno)
+	}
+
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatalf("open slave: %v", err)
+	}
+	t.Cleanup(func() { slave.Close() })
+	return master, slave
+}
+
+// echoEnabled Проверяет флаг ECHO терминала.
+func echoEnabled(t *testing.T, h Handle) bool {
+	t.Helper()
+	mode, err := getConsoleMode(h)
+	if err != nil {
+		t.Fatalf("getConsoleMode: %v", err)
+	}
+	return mode.Lflag&syscall.ECHO != 0
+}
+
+// readUntil Читает из master, пока не встретится want.
+func readUntil(t *testing.T, master *os.File, want string) string {
+	t.Helper()
+	var out bytes.Buffer
+	buf := make([]byte, 256)
+	master.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for !strings.Contains(out.String(), want) {
+		n, err := master.Read(buf)
+		if err != nil {
+			t.Fatalf("waiting for %q, got %q: %v", want, out.String(), err)
+		}
+		out.Write(buf[:n])
+	}
+	return out.String()
+}
+
+// drain Дочитывает все, что master успел получить за короткое время.
+func drain(master *os.File) string {
+	var out bytes.Buffer
+	buf := make([]byte, 256)
+	master.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	for {
+		n, err := master.Read(buf)
+		out.Write(buf[:n])
+		if err != nil {
+			return out.String()
+		}
+	}
+}
+
+// waitEchoDisabled Приглашение печатается до смены режима, поэтому ждем, пока эхо выключится.
+func waitEchoDisabled(t *testing.T, h Handle) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for echoEnabled(t, h) {
+		if time.Now().After(deadline) {
+			t.Fatal("echo was not disabled during password entry")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+type promptResult struct {
+	text string
+	err  error
+}
+
+// startPrompt Запускает consolePrompt на h в отдельной горутине.
+func startPrompt(h Handle, prompt string, echo bool) <-chan promptResult {
+	done := make(chan promptResult, 1)
+	go func() {
+		buf := make([]byte, 256)
+		n, err := consolePrompt(h, h, prompt, buf, echo)
+		done <- promptResult{string(buf[:n]), err}
+	}()
+	return done
+}
+
+func waitPrompt(t *testing.T, done <-chan promptResult) promptResult {
+	t.Helper()
+	select {
+	case res := <-done:
+		return res
+	case <-time.After(2 * time.Second):
+		t.Fatal("consolePrompt did not return")
+	}
+	return promptResult{}
+}
+
+func TestConsolePromptEchoOn(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	done := startPrompt(h, "Enter your name: ", true)
+	readUntil(t, master, "Enter your name: ")
+	master.Write([]byte("Anton\n"))
+
+	res := waitPrompt(t, done)
+	if res.err != nil || res.text != "Anton" {
+		t.Fatalf("got %q, %v; want %q", res.text, res.err, "Anton")
+	}
+	if out := drain(master); !strings.Contains(out, "Anton") {
+		t.Errorf("input was not echoed: %q", out)
+	}
+	if !echoEnabled(t, h) {
+		t.Error("echo is disabled after prompt")
+	}
+}
+
+func TestConsolePromptPasswordDisablesEcho(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	if !echoEnabled(t, h) {
+		t.Fatal("echo is disabled before prompt")
+	}
+
+	done := startPrompt(h, "Create password: ", false)
+	readUntil(t, master, "Create password: ")
+
+	waitEchoDisabled(t, h)
+
+	master.Write([]byte("secret\n"))
+	res := waitPrompt(t, done)
+	if res.err != nil || res.text != "secret" {
+		t.Fatalf("got %q, %v; want %q", res.text, res.err, "secret")
+	}
+	if out := drain(master); strings.Contains(out, "secret") {
+		t.Errorf("password was echoed: %q", out)
+	}
+	if !echoEnabled(t, h) {
+		t.Error("echo was not restored after password entry")
+	}
+}
+
+func TestConsolePromptRestoresEchoAfterReadError(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	// В неблокирующем режиме read на пустом терминале сразу вернет EAGAIN
+	if err := syscall.SetNonblock(h, true); err != nil {
+		t.Fatal(err)
+	}
+
+	done := startPrompt(h, "Create password: ", false)
+	res := waitPrompt(t, done)
+	if res.err == nil {
+		t.Fatalf("got %q; want read error", res.text)
+	}
+	readUntil(t, master, "Create password: ")
+	if !echoEnabled(t, h) {
+		t.Error("echo was not restored after read error")
+	}
+}
+
+func TestConsolePromptInterruptRestoresEcho(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	done := startPrompt(h, "Create password: ", false)
+	readUntil(t, master, "Create password: ")
+	// Эхо выключается после установки обработчиков, так что SIGINT не убьет тест
+	waitEchoDisabled(t, h)
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	res := waitPrompt(t, done)
+	if !errors.Is(res.err, ErrInterrupted) {
+		t.Fatalf("got %q, %v; want ErrInterrupted", res.text, res.err)
+	}
+	if !echoEnabled(t, h) {
+		t.Error("echo was not restored after SIGINT")
+	}
+
+	// После прерывания следующий вопрос работает как обычно
+	done = startPrompt(h, "Create password: ", false)
+	readUntil(t, master, "Create password: ")
+	waitEchoDisabled(t, h)
+	master.Write([]byte("again\n"))
+	if res := waitPrompt(t, done); res.err != nil || res.text != "again" {
+		t.Fatalf("got %q, %v; want %q", res.text, res.err, "again")
+	}
+}
+
+func TestConsolePromptNonTTY(t *testing.T) {
+	var p [2]int
+	if err := syscall.Pipe(p[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(p[0])
+
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+	out := Handle(null.Fd())
+
+	// Все ответы приходят одним куском: каждый вызов должен забрать ровно одну строку
+	syscall.Write(p[1], []byte("first\r\nsecond\nlast"))
+	syscall.Close(p[1])
+
+	buf := make([]byte, 256)
+	for _, want := range []string{"first", "second", "last"} {
+		n, err := consolePrompt(p[0], out, "> ", buf, false)
+		if err != nil || string(buf[:n]) != want {
+			t.Fatalf("got %q, %v; want %q", buf[:n], err, want)
+		}
+	}
+	if _, err := consolePrompt(p[0], out, "> ", buf, false); err != io.EOF {
+		t.Errorf("got %v at end of input, want io.EOF", err)
+	}
+}
+
+// Запись в неблокирующий канал больше его буфера: Console должна дождаться
+// читателя после EAGAIN и дописать все.
+func TestConsoleWriteNonblockingPipe(t *testing.T) {
+	var p [2]int
+	if err := syscall.Pipe(p[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(p[0])
+	defer syscall.Close(p[1])
+	if err := syscall.SetNonblock(p[1], true); err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64*1024) // 1 МБ, буфер канала 64 КБ
+	got := make(chan []byte)
+	go func() {
+		var all []byte
+		buf := make([]byte, 4096)
+		for len(all) < len(data) {
+			n, err := syscall.Read(p[0], buf)
+			if err != nil || n == 0 {
+				break
+			}
+			all = append(all, buf[:n]...)
+		}
+		got <- all
+	}()
+
+	n, err := fmt.Fprintf(NewConsole(Handle(p[1])), "%s", data)
+	if err != nil || n != len(data) {
+		t.Fatalf("wrote %d, %v; want %d", n, err, len(data))
+	}
+	select {
+	case all := <-got:
+		if !bytes.Equal(all, data) {
+			t.Errorf("reader got %d bytes, mismatch with written data", len(all))
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("reader did not finish")
+	}
+}
+
+// Виджеты по сценарию из канала: неверные ответы повторяются, конец ввода — io.EOF.
+func TestWidgetsScripted(t *testing.T) {
+	var p [2]int
+	if err := syscall.Pipe(p[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(p[0])
+
+	out, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	script := "0\nfour\n2\n\nmaybe\nн\n42\nsecret\nother\nsecret\nsecret\n"
+	syscall.Write(p[1], []byte(script))
+	syscall.Close(p[1])
+
+	ui := newUI(p[0], Handle(out.Fd()))
+	if n, err := ui.Menu("Menu", []string{"one", "two", "three"}); err != nil || n != 1 {
+		t.Fatalf("Menu = %d, %v; want 1", n, err)
+	}
+	if ok, err := ui.Confirm("Sure?", true); err != nil || !ok {
+		t.Fatalf("Confirm default = %v, %v; want true", ok, err)
+	}
+	if ok, err := ui.Confirm("Sure?", true); err != nil || ok {
+		t.Fatalf("Confirm = %v, %v; want false", ok, err)
+	}
+	if n, err := ui.Number("N: ", 1, 100); err != nil || n != 42 {
+		t.Fatalf("Number = %d, %v; want 42", n, err)
+	}
+	pass, err := ui.NewPassword("P: ", "Again: ", DefaultMask)
+	if err != nil || string(pass) != "secret" {
+		t.Fatalf("NewPassword = %q, %v; want secret", pass, err)
+	}
+	if _, err := ui.Input("More: ", nil); err != io.EOF {
+		t.Fatalf("Input at end = %v; want io.EOF", err)
+	}
+
+	log, _ := os.ReadFile(out.Name())
+	for _, want := range []string{"3. three", "number must be from 1 to 3", "not a number", "Please answer yes or no", "Passwords do not match"} {
+		if !bytes.Contains(log, []byte(want)) {
+			t.Errorf("output has no %q:\n%s", want, log)
+		}
+	}
+	if bytes.Contains(log, []byte("secret")) {
+		t.Error("password leaked to output")
+	}
+}
+
+// Ответы из SetAnswers печатаются после приглашения, пароль — маской.
+func TestScriptedAnswers(t *testing.T) {
+	out, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	h := Handle(out.Fd())
+
+	if err := SetAnswers(strings.NewReader("bob\r\nпароль\n")); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { script = nil }()
+
+	buf := make([]byte, 256)
+	n, err := consolePrompt(-1, h, "Name: ", buf, true)
+	if err != nil || string(buf[:n]) != "bob" {
+		t.Fatalf("got %q, %v; want bob", buf[:n], err)
+	}
+	pass, err := readPassword(-1, h, "Password: ", DefaultMask)
+	if err != nil || string(pass) != "пароль" {
+		t.Fatalf("got %q, %v; want пароль", pass, err)
+	}
+	if _, err := promptLine(-1, h, "More: ", true, 0); err != io.EOF {
+		t.Fatalf("got %v after last answer, want io.EOF", err)
+	}
+
+	log, _ := os.ReadFile(out.Name())
+	if want := "Name: bob\r\nPassword: ******\r\nMore: \r\n"; string(log) != want {
+		t.Errorf("output %q, want %q", log, want)
+	}
+}
+
+// Терминал с номером fd больше 1023 (в процессе много открытых файлов):
+// select с FdSet на таком номере падал бы с выходом за границы массива.
+func TestConsolePromptHighFD(t *testing.T) {
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &lim); err != nil || lim.Cur < 1200 {
+		t.Skip("RLIMIT_NOFILE is too low for a high fd")
+	}
+	master, slave := openPTY(t)
+	const high = 1100
+	if err := syscall.Dup3(int(slave.Fd()), high, syscall.O_CLOEXEC); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(high)
+
+	done := startPrompt(Handle(high), "Create password: ", false)
+	readUntil(t, master, "Create password: ")
+	waitEchoDisabled(t, Handle(high))
+
+	master.Write([]byte("secret\n"))
+	if res := waitPrompt(t, done); res.err != nil || res.text != "secret" {
+		t.Fatalf("got %q, %v; want %q", res.text, res.err, "secret")
+	}
+}
+
+func TestConsoleNoColorKeepsCursorControl(t *testing.T) {
+	master, slave := openPTY(t)
+	t.Setenv("TERM", "xterm")
+	t.Setenv("NO_COLOR", "1")
+
+	c := NewConsole(Handle(slave.Fd()))
+	if !c.Terminal() || c.Color() {
+		t.Fatalf("Terminal() = %v, Color() = %v; want true, false", c.Terminal(), c.Color())
+	}
+	c.Print(StyleError, "plain")
+	c.ClearLine()
+	c.WriteString("|")
+	if got := readUntil(t, master, "|"); got != "plain\r\x1b[2K|" {
+		t.Errorf("output %q, want color stripped and line cleared", got)
+	}
+}
+
+func TestConsoleRedirectedWritesNoEscapes(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	t.Setenv("TERM", "xterm")
+	t.Setenv("NO_COLOR", "")
+
+	c := NewConsole(Handle(w.Fd()))
+	c.Print(StyleTitle, "title\x1b]0;x\a")
+	c.ClearLine()
+	c.ClearScreen()
+	c.MoveTo(1, 1)
+	c.WriteString("end")
+	w.Close()
+	got, _ := io.ReadAll(r)
+	if string(got) != "titleend" {
+		t.Errorf("redirected output %q, want %q", got, "titleend")
+	}
+}
+
+func TestPromptLineLimitOnTTY(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	read := func(input string) (string, error) {
+		done := make(chan promptResult, 1)
+		go func() {
+			s, err := promptLine(h, h, "> ", true, 5)
+			done <- promptResult{s, err}
+		}()
+		readUntil(t, master, "> ")
+		master.Write([]byte(input))
+		res := waitPrompt(t, done)
+		drain(master)
+		return res.text, res.err
+	}
+
+	if s, err := read("abcde\n"); s != "abcde" || err != nil {
+		t.Errorf("line at limit: got %q, %v", s, err)
+	}
+	if _, err := read("abcdef\n"); !errors.Is(err, ErrLineTooLong) {
+		t.Errorf("line over limit: err = %v, want ErrLineTooLong", err)
+	}
+	if s, err := read("ok\n"); s != "ok" || err != nil {
+		t.Errorf("line after too long one: got %q, %v", s, err)
+	}
+}
+
+// Буфер ConsolePrompt кончается посреди многобайтного символа: символ
+// отбрасывается целиком, а остаток строки не попадает в следующий запрос.
+func TestConsolePromptSplitsOnRuneBoundary(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	read := func(size int, input string) promptResult {
+		done := make(chan promptResult, 1)
+		go func() {
+			buf := make([]byte, size)
+			n, err := consolePrompt(h, h, "> ", buf, true)
+			done <- promptResult{string(buf[:n]), err}
+		}()
+		readUntil(t, master, "> ")
+		master.Write([]byte(input))
+		res := waitPrompt(t, done)
+		drain(master)
+		return res
+	}
+
+	if res := read(4, "abcж\n"); res.err != nil || res.text != "abc" {
+		t.Errorf("split rune: got %q, %v; want %q", res.text, res.err, "abc")
+	}
+	if res := read(4, "ab€\n"); res.err != nil || res.text != "ab" {
+		t.Errorf("split 3-byte rune: got %q, %v; want %q", res.text, res.err, "ab")
+	}
+	if res := read(4, "next\n"); res.err != nil || res.text != "next" {
+		t.Errorf("line after truncated one: got %q, %v; want %q", res.text, res.err, "next")
+	}
+}
+
+// startPassword Запускает readPassword на h в отдельной горутине.
+func startPassword(h Handle, prompt string, mask rune) <-chan promptResult {
+	done := make(chan promptResult, 1)
+	go func() {
+		secret, err := readPassword(h, h, prompt, mask)
+		done <- promptResult{string(secret), err}
+	}()
+	return done
+}
+
+func TestReadPasswordMultibyteBackspace(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	done := startPassword(h, "Password: ", '*')
+	readUntil(t, master, "Password: ")
+	waitEchoDisabled(t, h)
+
+	// Backspace стирает последний символ целиком: и 3-байтный €, и 2-байтный ж
+	master.Write([]byte("aж€\x7f\x7fб\r"))
+	res := waitPrompt(t, done)
+	if res.err != nil || res.text != "aб" {
+		t.Fatalf("got %q, %v; want %q", res.text, res.err, "aб")
+	}
+	// По звездочке на символ и по одному стиранию на Backspace;
+	// \r\n терминал (OPOST) выводит как \r\r\n
+	if out := drain(master); out != "***\b \b\b \b*\r\r\n" {
+		t.Errorf("screen output %q", out)
+	}
+	if !echoEnabled(t, h) {
+		t.Error("echo is disabled after readPassword")
+	}
+}
+
+func TestReadPasswordKillLine(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	done := startPassword(h, "Password: ", 0)
+	readUntil(t, master, "Password: ")
+	waitEchoDisabled(t, h)
+
+	master.Write([]byte("wrong\x15ok\x7f\x7f\x7fпароль\r"))
+	if res := waitPrompt(t, done); res.err != nil || res.text != "пароль" {
+		t.Fatalf("got %q, %v; want %q", res.text, res.err, "пароль")
+	}
+	// Без маски на экран не выводится ничего, кроме перевода строки
+	if out := drain(master); out != "\r\r\n" {
+		t.Errorf("screen output %q, want only newline", out)
+	}
+}
+
+// Ctrl+C в сыром режиме (ISIG включен) приходит сигналом, а не байтом 0x03.
+// pty теста не управляющий терминал, поэтому сигнал посылаем сами.
+func TestReadPasswordInterrupt(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+
+	done := startPassword(h, "Password: ", '*')
+	readUntil(t, master, "Password: ")
+	waitEchoDisabled(t, h)
+
+	master.Write([]byte("abc"))
+	readUntil(t, master, "***")
+	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	if res := waitPrompt(t, done); !errors.Is(res.err, ErrInterrupted) || res.text != "" {
+		t.Fatalf("got %q, %v; want ErrInterrupted", res.text, res.err)
+	}
+	if !echoEnabled(t, h) {
+		t.Error("echo is disabled after SIGINT")
+	}
+}
+
+func TestLineEditorOnTTY(t *testing.T) {
+	master, slave := openPTY(t)
+	h := Handle(slave.Fd())
+	e := &LineEditor{hIn: h, hOut: h}
+
+	read := func(input string) promptResult {
+		done := make(chan promptResult, 1)
+		go func() {
+			line, err := e.ReadLine("> ")
+			done <- promptResult{line, err}
+		}()
+		readUntil(t, master, "> ")
+		waitEchoDisabled(t, h)
+		master.Write([]byte(input))
+		res := waitPrompt(t, done)
+		drain(master)
+		return res
+	}
+
+	// Стрелки, Home/End, Delete и Backspace по многобайтным символам
+	if res := read("мир\x1b[H\x1b[3~M\x1b[Fx\x7f!\r"); res.err != nil || res.text != "Mир!" {
+		t.Fatalf("got %q, %v; want %q", res.text, res.err, "Mир!")
+	}
+	if res := read("one two\x17three\r"); res.err != nil || res.text != "one three" {
+		t.Fatalf("Ctrl+W: got %q, %v; want %q", res.text, res.err, "one three")
+	}
+	// Стрелка вверх достает предыдущую строку
+	if res := read("\x1b[A\x1b[A\x1b[D\x7f\r"); res.err != nil || res.text != "Mи!" {
+		t.Fatalf("history: got %q, %v; want %q", res.text, res.err, "Mи!")
+	}
+	if res := read("\x04"); res.err != io.EOF {
+		t.Fatalf("Ctrl+D on empty line: got %q, %v; want io.EOF", res.text, res.err)
+	}
+	if !echoEnabled(t, h) {
+		t.Error("echo is disabled after ReadLine")
+	}
+}
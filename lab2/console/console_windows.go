@@ -0,0 +1,120 @@
+//go:build windows
+
+package console
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	STD_INPUT_HANDLE  = 0xFFFFFFF6 // -10
+	STD_OUTPUT_HANDLE = 0xFFFFFFF5 // -11
+	ENABLE_ECHO_INPUT = 0x0004
+
+	ENABLE_LINE_INPUT             = 0x0002
+	ENABLE_VIRTUAL_TERMINAL_INPUT = 0x0200
+
+	// Флаг для дескриптора вывода
+	ENABLE_VIRTUAL_TERMINAL_PROCESSING = 0x0004
+)
+
+// kernel32 DLL для функций работы с режимом консоли (в syscall их может не быть напрямую)
+var (
+	modkernel32        = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleMode = modkernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode = modkernel32.NewProc("SetConsoleMode")
+)
+
+// Handle Дескриптор консоли Win32.
+type Handle = syscall.Handle
+
+// consoleMode Флаги режима консоли (ENABLE_*).
+type consoleMode uint32
+
+// GetStdHandle Возвращает стандартный дескриптор.
+func GetStdHandle(n int) (Handle, error) {
+	return syscall.GetStdHandle(n)
+}
+
+// writeFile Один вызов WriteFile.
+func writeFile(h Handle, b []byte) (int, error) {
+	var written uint32
+	err := syscall.WriteFile(h, b, &written, nil)
+	return int(written), err
+}
+
+// retryWrite В Windows WriteFile не прерывается сигналами и не бывает
+// неблокирующим, повторять после ошибки нечего.
+func retryWrite(h Handle, err error) bool {
+	return false
+}
+
+// readFile Один вызов ReadFile.
+// Закрытый с другой стороны канал (ERROR_BROKEN_PIPE) считается концом файла.
+func readFile(h Handle, b []byte) (int, error) {
+	var read uint32
+	err := syscall.ReadFile(h, b, &read, nil)
+	if err == syscall.ERROR_BROKEN_PIPE {
+		return int(read), nil
+	}
+	return int(read), err
+}
+
+// getConsoleMode Вызывает GetConsoleMode.
+func getConsoleMode(h Handle) (consoleMode, error) {
+	var mode uint32
+	r1, _, err := procGetConsoleMode.Call(uintptr(h), uintptr(unsafe.Pointer(&mode)))
+	if r1 == 0 {
+		return 0, err
+	}
+	return consoleMode(mode), nil
+}
+
+// setConsoleMode Вызывает SetConsoleMode.
+func setConsoleMode(h Handle, mode consoleMode) error {
+	r1, _, err := procSetConsoleMode.Call(uintptr(h), uintptr(mode))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
+
+// disableEcho Сбрасывает ENABLE_ECHO_INPUT.
+func disableEcho(mode consoleMode) consoleMode {
+	return mode &^ ENABLE_ECHO_INPUT
+}
+
+// makeRaw Режим для построчного редактора: без построчного ввода и эха,
+// клавиши-стрелки приходят ANSI-последовательностями (ENABLE_VIRTUAL_TERMINAL_INPUT).
+// ENABLE_PROCESSED_INPUT оставляем, чтобы Ctrl+C обрабатывал modeGuard.
+func makeRaw(mode consoleMode) consoleMode {
+	mode &^= ENABLE_LINE_INPUT | ENABLE_ECHO_INPUT
+	mode |= ENABLE_VIRTUAL_TERMINAL_INPUT
+	return mode
+}
+
+// enableVirtualTerminal Включает разбор ANSI-последовательностей на выводе.
+// Возвращает функцию, возвращающую прежний режим.
+func enableVirtualTerminal(h Handle) func() {
+	mode, err := getConsoleMode(h)
+	if err != nil {
+		return func() {}
+	}
+	setConsoleMode(h, mode|ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+	return func() { setConsoleMode(h, mode) }
+}
+
+// supportsANSI Вывод — консоль, понимающая ANSI-последовательности. Разбор
+// последовательностей включается и остается включенным; старые консоли,
+// где его нет, SetConsoleMode отвергает.
+func supportsANSI(h Handle) bool {
+	mode, err := getConsoleMode(h)
+	if err != nil {
+		return false
+	}
+	if mode&ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return setConsoleMode(h, mode|ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
@@ -0,0 +1,294 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxHistory Сколько строк истории хранит LineEditor по умолчанию.
+const DefaultMaxHistory = 100
+
+// LineEditor Построчный редактор поверх сырого режима консоли: Backspace/Delete,
+// стрелки влево/вправо, Home/End (и Ctrl+A/Ctrl+E), Ctrl+U — стереть до начала,
+// Ctrl+W — стереть слово, история по стрелкам вверх/вниз и дополнение по Tab.
+// Если ввод не консоль, ReadLine просто читает строку.
+type LineEditor struct {
+	hIn, hOut Handle
+
+	// History Введенные строки, от старых к новым. Можно заполнить заранее.
+	History []string
+	// MaxHistory Предел длины истории (0 — DefaultMaxHistory).
+	MaxHistory int
+	// Complete Возвращает варианты дополнения для строки line.
+	// Один вариант подставляется сразу, несколько — дополняются до общего
+	// префикса, а повторный Tab выводит их список.
+	Complete func(line string) []string
+}
+
+// NewLineEditor Редактор на стандартных дескрипторах.
+func NewLineEditor() *LineEditor {
+	hIn, _ := GetStdHandle(STD_INPUT_HANDLE)
+	hOut, _ := GetStdHandle(STD_OUTPUT_HANDLE)
+	return &LineEditor{hIn: hIn, hOut: hOut}
+}
+
+// editState Состояние редактируемой строки.
+type editState struct {
+	prompt  string
+	line    []rune
+	pos     int // позиция курсора в рунах
+	histIdx int // текущая строка истории; len(History) — новая строка
+	draft   []rune
+	lastTab bool
+}
+
+// ReadLine Выводит приглашение и читает строку с редактированием.
+// Ctrl+D на пустой строке дает io.EOF, Ctrl+C — ErrInterrupted.
+func (e *LineEditor) ReadLine(prompt string) (string, error) {
+	if answer, ok, err := scriptedAnswer(e.hOut, prompt, false, 0); ok {
+		if err == nil {
+			e.addHistory(answer)
+		}
+		return answer, err
+	}
+
+	if _, err := NewConsole(e.hOut).WriteString(prompt); err != nil {
+		return "", err
+	}
+
+	original, err := getConsoleMode(e.hIn)
+	if err != nil {
+		// Не консоль — редактировать нечего
+		line, err := readLineBytes(e.hIn)
+		return string(line), err
+	}
+
+	guard, err := enterMode(e.hIn, original, makeRaw(original))
+	if err != nil {
+		return "", err
+	}
+	defer guard.Restore()
+	defer enableVirtualTerminal(e.hOut)()
+
+	st := &editState{prompt: prompt, histIdx: len(e.History)}
+	keys := newKeyReader(guard.Read)
+
+	for {
+		key, err := keys.ReadKey()
+		if err != nil {
+			e.write("\r\n")
+			return "", err
+		}
+
+		if key.Code != KeyTab {
+			st.lastTab = false
+		}
+
+		switch key.Code {
+		case KeyEnter:
+			e.write("\r\n")
+			line := string(st.line)
+			e.addHistory(line)
+			return line, nil
+		case KeyCtrlC:
+			e.write("^C\r\n")
+			return "", ErrInterrupted
+		case KeyCtrlD:
+			if len(st.line) == 0 {
+				e.write("\r\n")
+				return "", io.EOF
+			}
+			st.deleteAt(st.pos)
+		case KeyRune:
+			st.insert(key.Rune)
+		case KeyBackspace:
+			if st.pos > 0 {
+				st.pos--
+				st.deleteAt(st.pos)
+			}
+		case KeyDelete:
+			st.deleteAt(st.pos)
+		case KeyLeft:
+			if st.pos > 0 {
+				st.pos--
+			}
+		case KeyRight:
+			if st.pos < len(st.line) {
+				st.pos++
+			}
+		case KeyHome:
+			st.pos = 0
+		case KeyEnd:
+			st.pos = len(st.line)
+		case KeyCtrlU:
+			st.line = append(st.line[:0:0], st.line[st.pos:]...)
+			st.pos = 0
+		case KeyCtrlW:
+			st.deleteWord()
+		case KeyUp:
+			e.historyMove(st, -1)
+		case KeyDown:
+			e.historyMove(st, 1)
+		case KeyTab:
+			e.complete(st)
+		default:
+			continue
+		}
+		e.refresh(st)
+	}
+}
+
+// write Вывод без проверки ошибок: отрисовка строки не критична.
+func (e *LineEditor) write(s string) {
+	NewConsole(e.hOut).WriteString(s)
+}
+
+// refresh Перерисовывает строку: в начало, приглашение, текст, очистка хвоста
+// и возврат курсора на место.
+func (e *LineEditor) refresh(st *editState) {
+	var b strings.Builder
+	b.WriteString("\r")
+	b.WriteString(st.prompt)
+	b.WriteString(string(st.line))
+	b.WriteString("\x1b[K")
+	if back := len(st.line) - st.pos; back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+	e.write(b.String())
+}
+
+func (st *editState) insert(r rune) {
+	st.line = append(st.line, 0)
+	copy(st.line[st.pos+1:], st.line[st.pos:])
+	st.line[st.pos] = r
+	st.pos++
+}
+
+func (st *editState) deleteAt(i int) {
+	if i < len(st.line) {
+		st.line = append(st.line[:i], st.line[i+1:]...)
+	}
+}
+
+// deleteWord Ctrl+W: пробелы перед курсором и слово перед ними.
+func (st *editState) deleteWord() {
+	start := st.pos
+	for start > 0 && unicode.IsSpace(st.line[start-1]) {
+		start--
+	}
+	for start > 0 && !unicode.IsSpace(st.line[start-1]) {
+		start--
+	}
+	st.line = append(st.line[:start], st.line[st.pos:]...)
+	st.pos = start
+}
+
+func (st *editState) set(line []rune) {
+	st.line = append(st.line[:0:0], line...)
+	st.pos = len(st.line)
+}
+
+// historyMove Листает историю. Недописанная строка сохраняется и возвращается
+// при выходе вниз за конец истории.
+func (e *LineEditor) historyMove(st *editState, dir int) {
+	next := st.histIdx + dir
+	if next < 0 || next > len(e.History) {
+		return
+	}
+	if st.histIdx == len(e.History) {
+		st.draft = append(st.draft[:0], st.line...)
+	}
+	st.histIdx = next
+	if next == len(e.History) {
+		st.set(st.draft)
+	} else {
+		st.set([]rune(e.History[next]))
+	}
+}
+
+// addHistory Запоминает непустую строку, если она не повторяет последнюю.
+func (e *LineEditor) addHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(e.History); n > 0 && e.History[n-1] == line {
+		return
+	}
+	e.History = append(e.History, line)
+
+	limit := e.MaxHistory
+	if limit <= 0 {
+		limit = DefaultMaxHistory
+	}
+	if len(e.History) > limit {
+		e.History = e.History[len(e.History)-limit:]
+	}
+}
+
+// complete Tab: дополнение строки до курсора.
+func (e *LineEditor) complete(st *editState) {
+	if e.Complete == nil {
+		return
+	}
+	head, tail := string(st.line[:st.pos]), st.line[st.pos:]
+	options := e.Complete(head)
+
+	switch {
+	case len(options) == 0:
+		e.write("\a")
+	case len(options) == 1:
+		st.set([]rune(options[0]))
+		st.line = append(st.line, tail...)
+	default:
+		prefix := commonPrefix(options)
+		if len([]rune(prefix)) > len([]rune(head)) {
+			st.set([]rune(prefix))
+			st.line = append(st.line, tail...)
+		} else if st.lastTab {
+			// Второй Tab подряд — показываем варианты под строкой
+			e.write("\r\n" + strings.Join(options, "  ") + "\r\n")
+		} else {
+			e.write("\a")
+		}
+	}
+	st.lastTab = true
+}
+
+// commonPrefix Общий префикс строк (по рунам).
+func commonPrefix(options []string) string {
+	prefix := []rune(options[0])
+	for _, o := range options[1:] {
+		r := []rune(o)
+		n := 0
+		for n < len(prefix) && n < len(r) && prefix[n] == r[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// readLineBytes Читает строку произвольной длины побайтно, без \r\n на конце.
+func readLineBytes(h Handle) ([]byte, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := readFile(h, b)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			if len(line) == 0 {
+				return nil, io.EOF
+			}
+			break
+		}
+		if b[0] == '\n' {
+			break
+		}
+		line = append(line, b[0])
+	}
+	return line[:trimNewline(line, len(line))], nil
+}
@@ -0,0 +1,144 @@
+package console
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// newState Строка line с курсором на позиции pos (в рунах).
+func newState(line string, pos int) *editState {
+	return &editState{line: []rune(line), pos: pos}
+}
+
+func TestEditState(t *testing.T) {
+	tests := []struct {
+		name    string
+		st      *editState
+		edit    func(st *editState)
+		line    string
+		wantPos int
+	}{
+		{"insert at end", newState("ab", 2), func(st *editState) { st.insert('ж') }, "abж", 3},
+		{"insert in middle", newState("ac", 1), func(st *editState) { st.insert('b') }, "abc", 2},
+		{"insert at start", newState("bc", 0), func(st *editState) { st.insert('a') }, "abc", 1},
+		{"delete under cursor", newState("aжc", 1), func(st *editState) { st.deleteAt(st.pos) }, "ac", 1},
+		{"delete at end", newState("ab", 2), func(st *editState) { st.deleteAt(st.pos) }, "ab", 2},
+		{"delete word", newState("git commit", 10), (*editState).deleteWord, "git ", 4},
+		{"delete word and spaces", newState("git commit  ", 12), (*editState).deleteWord, "git ", 4},
+		{"delete word before cursor", newState("один два три", 8), (*editState).deleteWord, "один  три", 5},
+		{"delete word at start", newState("abc", 0), (*editState).deleteWord, "abc", 0},
+		{"set", newState("old", 1), func(st *editState) { st.set([]rune("новая")) }, "новая", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.edit(tt.st)
+			if string(tt.st.line) != tt.line || tt.st.pos != tt.wantPos {
+				t.Errorf("got %q at %d, want %q at %d", string(tt.st.line), tt.st.pos, tt.line, tt.wantPos)
+			}
+		})
+	}
+}
+
+// Строки истории не должны меняться от правок строки, взятой из истории
+func TestEditStateSetCopies(t *testing.T) {
+	src := []rune("abc")
+	st := &editState{}
+	st.set(src)
+	st.pos = 1
+	st.insert('x')
+	if string(src) != "abc" {
+		t.Errorf("source changed to %q", string(src))
+	}
+}
+
+func TestHistoryMove(t *testing.T) {
+	e := &LineEditor{History: []string{"first", "second"}}
+	st := &editState{histIdx: len(e.History)}
+	st.set([]rune("draft"))
+
+	steps := []struct {
+		dir  int
+		want string
+	}{
+		{-1, "second"},
+		{-1, "first"},
+		{-1, "first"}, // выше начала истории не уходим
+		{1, "second"},
+		{1, "draft"}, // недописанная строка вернулась
+		{1, "draft"},
+	}
+	for i, s := range steps {
+		e.historyMove(st, s.dir)
+		if got := string(st.line); got != s.want || st.pos != len(st.line) {
+			t.Fatalf("step %d: got %q at %d, want %q at end", i, got, st.pos, s.want)
+		}
+	}
+}
+
+func TestAddHistory(t *testing.T) {
+	e := &LineEditor{MaxHistory: 3}
+	for _, line := range []string{"a", "", "  ", "b", "b", "c", "d"} {
+		e.addHistory(line)
+	}
+	if got := strings.Join(e.History, ","); got != "b,c,d" {
+		t.Errorf("history %q, want %q", got, "b,c,d")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	commands := []string{"help", "history", "hello", "quit"}
+	e := &LineEditor{hOut: Handle(devNull.Fd()), Complete: func(line string) []string {
+		var options []string
+		for _, c := range commands {
+			if strings.HasPrefix(c, line) {
+				options = append(options, c)
+			}
+		}
+		return options
+	}}
+
+	tests := []struct {
+		line    string
+		pos     int
+		want    string
+		wantPos int
+	}{
+		{"q", 1, "quit", 4},
+		{"h", 1, "h", 1},              // общий префикс не длиннее введенного
+		{"hel", 3, "hel", 3},          // help и hello
+		{"hi", 2, "history", 7},       // единственный вариант
+		{"he tail", 2, "hel tail", 3}, // хвост после курсора сохраняется
+		{"x", 1, "x", 1},
+	}
+	for _, tt := range tests {
+		st := newState(tt.line, tt.pos)
+		e.complete(st)
+		if string(st.line) != tt.want || st.pos != tt.wantPos || !st.lastTab {
+			t.Errorf("complete(%q at %d) = %q at %d, want %q at %d", tt.line, tt.pos, string(st.line), st.pos, tt.want, tt.wantPos)
+		}
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		options []string
+		want    string
+	}{
+		{[]string{"help", "hello"}, "hel"},
+		{[]string{"привет", "прием"}, "при"},
+		{[]string{"abc", "xyz"}, ""},
+		{[]string{"same"}, "same"},
+	}
+	for _, tt := range tests {
+		if got := commonPrefix(tt.options); got != tt.want {
+			t.Errorf("commonPrefix(%q) = %q, want %q", tt.options, got, tt.want)
+		}
+	}
+}
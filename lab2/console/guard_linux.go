@@ -0,0 +1,167 @@
+//go:build linux
+
+package console
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// modeGuard Держит терминал в измененном режиме и гарантирует возврат
+// исходного режима при выходе из чтения или по сигналу:
+//
+//	SIGINT, SIGTERM — режим восстанавливается, ожидающее чтение возвращает ErrInterrupted;
+//	SIGTSTP         — режим восстанавливается, процесс останавливается как обычно;
+//	SIGCONT         — после продолжения измененный режим включается снова.
+type modeGuard struct {
+	h        Handle
+	original consoleMode
+	active   consoleMode
+
+	sigs chan os.Signal
+	done chan struct{}
+	// Канал для пробуждения select в Read при прерывании
+	wakeR, wakeW int
+
+	mu          sync.Mutex
+	interrupted os.Signal
+	restored    bool
+}
+
+// enterMode Включает режим active, запомнив original для восстановления.
+func enterMode(h Handle, original, active consoleMode) (*modeGuard, error) {
+	var p [2]int
+	if err := syscall.Pipe2(p[:], syscall.O_CLOEXEC|syscall.O_NONBLOCK); err != nil {
+		return nil, err
+	}
+
+	g := &modeGuard{
+		h:        h,
+		original: original,
+		active:   active,
+		sigs:     make(chan os.Signal, 4),
+		done:     make(chan struct{}),
+		wakeR:    p[0],
+		wakeW:    p[1],
+	}
+
+	// Обработчики ставим до смены режима: если эхо выключено, сигнал уже не потеряется
+	signal.Notify(g.sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGTSTP, syscall.SIGCONT)
+	if err := setConsoleMode(h, active); err != nil {
+		signal.Stop(g.sigs)
+		syscall.Close(g.wakeR)
+		syscall.Close(g.wakeW)
+		return nil, err
+	}
+	go g.watch()
+	return g, nil
+}
+
+// watch Обрабатывает сигналы, пока guard активен.
+func (g *modeGuard) watch() {
+	for {
+		select {
+		case <-g.done:
+			return
+		case sig := <-g.sigs:
+			g.mu.Lock()
+			if g.restored {
+				g.mu.Unlock()
+				return
+			}
+			switch sig {
+			case syscall.SIGINT, syscall.SIGTERM:
+				setConsoleMode(g.h, g.original)
+				g.interrupted = sig
+				syscall.Write(g.wakeW, []byte{0})
+			case syscall.SIGTSTP:
+				// Возвращаем терминал оболочке и останавливаемся по-настоящему:
+				// снимаем свой обработчик и шлем SIGTSTP себе еще раз
+				setConsoleMode(g.h, g.original)
+				signal.Reset(syscall.SIGTSTP)
+				syscall.Kill(syscall.Getpid(), syscall.SIGTSTP)
+			case syscall.SIGCONT:
+				// Продолжили после остановки (fg/bg) — снова прячем ввод
+				signal.Notify(g.sigs, syscall.SIGTSTP)
+				if g.interrupted == nil {
+					setConsoleMode(g.h, g.active)
+				}
+			}
+			g.mu.Unlock()
+		}
+	}
+}
+
+// Read Читает из терминала, пока не придут данные или сигнал прерывания.
+// Неблокирующий дескриптор читается сразу, без ожидания.
+func (g *modeGuard) Read(buf []byte) (int, error) {
+	flags, err := fcntlGetFlags(g.h)
+	if err != nil {
+		return 0, err
+	}
+	if flags&syscall.O_NONBLOCK != 0 {
+		return readFile(g.h, buf)
+	}
+
+	// poll, а не select: FdSet вмещает только fd < 1024, а номера tty и
+	// канала пробуждения в процессе с множеством открытых файлов бывают больше
+	fds := []unix.PollFd{
+		{Fd: int32(g.h), Events: unix.POLLIN},
+		{Fd: int32(g.wakeR), Events: unix.POLLIN},
+	}
+	for {
+		_, err := unix.Poll(fds, -1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		g.mu.Lock()
+		sig := g.interrupted
+		g.mu.Unlock()
+		if sig != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInterrupted, sig)
+		}
+		// HUP и ERR тоже: read вернет конец файла или ошибку
+		if fds[0].Revents&(unix.POLLIN|unix.POLLHUP|unix.POLLERR|unix.POLLNVAL) != 0 {
+			return readFile(g.h, buf)
+		}
+	}
+}
+
+// Restore Возвращает исходный режим и снимает обработчики сигналов.
+// Повторный вызов ничего не делает.
+func (g *modeGuard) Restore() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.restored {
+		return nil
+	}
+	g.restored = true
+
+	signal.Stop(g.sigs)
+	close(g.done)
+	syscall.Close(g.wakeR)
+	syscall.Close(g.wakeW)
+
+	if g.interrupted != nil {
+		return nil // уже восстановлен обработчиком
+	}
+	return setConsoleMode(g.h, g.original)
+}
+
+// fcntlGetFlags Флаги открытого файла (F_GETFL).
+func fcntlGetFlags(h Handle) (int, error) {
+	r, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(h), syscall.F_GETFL, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r), nil
+}
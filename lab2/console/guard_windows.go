@@ -0,0 +1,108 @@
+//go:build windows
+
+package console
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// modeGuard Держит консоль в измененном режиме и гарантирует возврат
+// исходного режима при выходе из чтения или по Ctrl+C/Ctrl+Break/закрытию окна.
+// Ожидающий ReadFile отменяется через CancelIoEx и возвращает ErrInterrupted.
+// Аналогов SIGTSTP/SIGCONT в консоли Windows нет.
+type modeGuard struct {
+	h        Handle
+	original consoleMode
+
+	sigs chan os.Signal
+	done chan struct{}
+	intr chan struct{} // закрывается при прерывании
+
+	mu          sync.Mutex
+	interrupted os.Signal
+	restored    bool
+}
+
+// enterMode Включает режим active, запомнив original для восстановления.
+func enterMode(h Handle, original, active consoleMode) (*modeGuard, error) {
+	g := &modeGuard{
+		h:        h,
+		original: original,
+		sigs:     make(chan os.Signal, 1),
+		done:     make(chan struct{}),
+		intr:     make(chan struct{}),
+	}
+
+	signal.Notify(g.sigs, os.Interrupt, syscall.SIGTERM)
+	if err := setConsoleMode(h, active); err != nil {
+		signal.Stop(g.sigs)
+		return nil, err
+	}
+	go g.watch()
+	return g, nil
+}
+
+// watch Ждет сигнала, пока guard активен.
+func (g *modeGuard) watch() {
+	select {
+	case <-g.done:
+	case sig := <-g.sigs:
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.restored {
+			return
+		}
+		setConsoleMode(g.h, g.original)
+		g.interrupted = sig
+		close(g.intr)
+		syscall.CancelIoEx(g.h, nil)
+	}
+}
+
+// ctrlHandlerDelay Сколько ждать обработчик Ctrl+C после пустого чтения.
+const ctrlHandlerDelay = 100 * time.Millisecond
+
+// Read Читает из консоли, пока не придут данные или сигнал прерывания.
+func (g *modeGuard) Read(buf []byte) (int, error) {
+	n, err := readFile(g.h, buf)
+	if n == 0 {
+		// По Ctrl+C ReadFile возвращается с пустым результатом, а обработчик
+		// сигнала работает в другом потоке и может не успеть выставить флаг
+		select {
+		case <-g.intr:
+		case <-time.After(ctrlHandlerDelay):
+		}
+	}
+
+	g.mu.Lock()
+	sig := g.interrupted
+	g.mu.Unlock()
+	if sig != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInterrupted, sig)
+	}
+	return n, err
+}
+
+// Restore Возвращает исходный режим и снимает обработчики.
+// Повторный вызов ничего не делает.
+func (g *modeGuard) Restore() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.restored {
+		return nil
+	}
+	g.restored = true
+
+	signal.Stop(g.sigs)
+	close(g.done)
+
+	if g.interrupted != nil {
+		return nil // уже восстановлен обработчиком
+	}
+	return setConsoleMode(g.h, g.original)
+}
@@ -0,0 +1,182 @@
+package console
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// Key Нажатая клавиша. Для обычных символов Code == KeyRune, а сам символ в Rune.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// KeyCode Код клавиши.
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyDelete
+	KeyLeft
+	KeyRight
+	KeyUp
+	KeyDown
+	KeyHome
+	KeyEnd
+	KeyCtrlC
+	KeyCtrlD
+	KeyCtrlU
+	KeyCtrlW
+	KeyEscape
+	KeyUnknown
+)
+
+// keyReader Разбирает поток байт из консоли на клавиши: UTF-8 символы,
+// управляющие символы и ANSI-последовательности стрелок и Home/End.
+type keyReader struct {
+	read func([]byte) (int, error)
+	buf  []byte
+	pos  int
+	n    int
+}
+
+func newKeyReader(read func([]byte) (int, error)) *keyReader {
+	return &keyReader{read: read, buf: make([]byte, 256)}
+}
+
+// byte Следующий байт из буфера, при необходимости дочитывает консоль.
+func (r *keyReader) byte() (byte, error) {
+	for r.pos >= r.n {
+		n, err := r.read(r.buf)
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			return 0, io.EOF
+		}
+		r.pos, r.n = 0, n
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+// buffered Есть ли еще байты, пришедшие тем же чтением (часть последовательности).
+func (r *keyReader) buffered() bool {
+	return r.pos < r.n
+}
+
+// ReadKey Читает одну клавишу.
+func (r *keyReader) ReadKey() (Key, error) {
+	b, err := r.byte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch b {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case '\t':
+		return Key{Code: KeyTab}, nil
+	case 0x7f, 0x08:
+		return Key{Code: KeyBackspace}, nil
+	case 0x01: // Ctrl+A
+		return Key{Code: KeyHome}, nil
+	case 0x05: // Ctrl+E
+		return Key{Code: KeyEnd}, nil
+	case 0x02: // Ctrl+B
+		return Key{Code: KeyLeft}, nil
+	case 0x06: // Ctrl+F
+		return Key{Code: KeyRight}, nil
+	case 0x10: // Ctrl+P
+		return Key{Code: KeyUp}, nil
+	case 0x0e: // Ctrl+N
+		return Key{Code: KeyDown}, nil
+	case 0x03:
+		return Key{Code: KeyCtrlC}, nil
+	case 0x04:
+		return Key{Code: KeyCtrlD}, nil
+	case 0x15:
+		return Key{Code: KeyCtrlU}, nil
+	case 0x17:
+		return Key{Code: KeyCtrlW}, nil
+	case 0x1b:
+		return r.escape()
+	}
+
+	if b < 0x20 {
+		return Key{Code: KeyUnknown}, nil
+	}
+	if b < utf8.RuneSelf {
+		return Key{Code: KeyRune, Rune: rune(b)}, nil
+	}
+
+	// Многобайтовый UTF-8 символ: добираем продолжение
+	seq := []byte{b}
+	for !utf8.FullRune(seq) {
+		c, err := r.byte()
+		if err != nil {
+			return Key{}, err
+		}
+		seq = append(seq, c)
+	}
+	ch, _ := utf8.DecodeRune(seq)
+	if ch == utf8.RuneError {
+		return Key{Code: KeyUnknown}, nil
+	}
+	return Key{Code: KeyRune, Rune: ch}, nil
+}
+
+// escape Разбирает ESC [ ... и ESC O ... . Одиночный Esc (без продолжения в том же чтении) — KeyEscape.
+func (r *keyReader) escape() (Key, error) {
+	if !r.buffered() {
+		return Key{Code: KeyEscape}, nil
+	}
+	b, err := r.byte()
+	if err != nil {
+		return Key{}, err
+	}
+	if b != '[' && b != 'O' {
+		return Key{Code: KeyUnknown}, nil
+	}
+
+	// Параметры (цифры и ';') и финальный байт
+	var param []byte
+	for {
+		c, err := r.byte()
+		if err != nil {
+			return Key{}, err
+		}
+		if (c >= '0' && c <= '9') || c == ';' {
+			param = append(param, c)
+			continue
+		}
+		switch c {
+		case 'A':
+			return Key{Code: KeyUp}, nil
+		case 'B':
+			return Key{Code: KeyDown}, nil
+		case 'C':
+			return Key{Code: KeyRight}, nil
+		case 'D':
+			return Key{Code: KeyLeft}, nil
+		case 'H':
+			return Key{Code: KeyHome}, nil
+		case 'F':
+			return Key{Code: KeyEnd}, nil
+		case '~':
+			switch string(param) {
+			case "1", "7":
+				return Key{Code: KeyHome}, nil
+			case "4", "8":
+				return Key{Code: KeyEnd}, nil
+			case "3":
+				return Key{Code: KeyDelete}, nil
+			}
+		}
+		return Key{Code: KeyUnknown}, nil
+	}
+}
@@ -0,0 +1,60 @@
+package console
+
+import (
+	"io"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []Key
+	}{
+		{"ASCII", []string{"a1 "}, []Key{{KeyRune, 'a'}, {KeyRune, '1'}, {KeyRune, ' '}}},
+		{"multibyte", []string{"ж€😀"}, []Key{{KeyRune, 'ж'}, {KeyRune, '€'}, {KeyRune, '😀'}}},
+		{"multibyte split across reads", []string{"\xe2", "\x82", "\xac"}, []Key{{KeyRune, '€'}}},
+		{"invalid UTF-8", []string{"\xff"}, []Key{{Code: KeyUnknown}}},
+		{"enter", []string{"\r\n"}, []Key{{Code: KeyEnter}, {Code: KeyEnter}}},
+		{"backspace", []string{"\x7f\x08"}, []Key{{Code: KeyBackspace}, {Code: KeyBackspace}}},
+		{"control keys", []string{"\t\x01\x05\x02\x06\x10\x0e\x03\x04\x15\x17\x1a"}, []Key{
+			{Code: KeyTab}, {Code: KeyHome}, {Code: KeyEnd}, {Code: KeyLeft}, {Code: KeyRight},
+			{Code: KeyUp}, {Code: KeyDown}, {Code: KeyCtrlC}, {Code: KeyCtrlD}, {Code: KeyCtrlU},
+			{Code: KeyCtrlW}, {Code: KeyUnknown},
+		}},
+		{"CSI arrows", []string{"\x1b[A\x1b[B\x1b[C\x1b[D"}, []Key{{Code: KeyUp}, {Code: KeyDown}, {Code: KeyRight}, {Code: KeyLeft}}},
+		{"SS3 arrows", []string{"\x1bOA\x1bOH\x1bOF"}, []Key{{Code: KeyUp}, {Code: KeyHome}, {Code: KeyEnd}}},
+		{"CSI home and end", []string{"\x1b[H\x1b[F\x1b[1~\x1b[4~\x1b[7~\x1b[8~"}, []Key{
+			{Code: KeyHome}, {Code: KeyEnd}, {Code: KeyHome}, {Code: KeyEnd}, {Code: KeyHome}, {Code: KeyEnd},
+		}},
+		{"delete", []string{"\x1b[3~"}, []Key{{Code: KeyDelete}}},
+		{"modifiers", []string{"\x1b[1;5C"}, []Key{{Code: KeyRight}}},
+		{"unknown CSI", []string{"\x1b[5~\x1b[Zx"}, []Key{{Code: KeyUnknown}, {Code: KeyUnknown}, {KeyRune, 'x'}}},
+		{"lone escape", []string{"\x1b", "a"}, []Key{{Code: KeyEscape}, {KeyRune, 'a'}}},
+		{"alt+key", []string{"\x1bx"}, []Key{{Code: KeyUnknown}}},
+		{"sequence split after ESC [", []string{"\x1b[", "A"}, []Key{{Code: KeyUp}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newKeyReader(chunkReader(tt.chunks...))
+			for i, want := range tt.want {
+				got, err := r.ReadKey()
+				if err != nil || got != want {
+					t.Fatalf("key %d = %+v, %v; want %+v", i, got, err, want)
+				}
+			}
+			if got, err := r.ReadKey(); err != io.EOF {
+				t.Errorf("extra key %+v, %v; want io.EOF", got, err)
+			}
+		})
+	}
+}
+
+func TestReadKeyEOFInsideSequence(t *testing.T) {
+	for _, input := range []string{"\xd0", "\x1b[1"} {
+		r := newKeyReader(chunkReader(input))
+		if got, err := r.ReadKey(); err != io.EOF {
+			t.Errorf("%q: got %+v, %v; want io.EOF", input, got, err)
+		}
+	}
+}
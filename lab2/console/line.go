@@ -0,0 +1,148 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// DefaultMaxLine Предел длины строки для PromptLine по умолчанию, в байтах.
+const DefaultMaxLine = 64 * 1024
+
+var (
+	// ErrLineTooLong Строка длиннее заданного предела. Остаток строки уже
+	// вычитан, следующий запрос начнет со следующей строки.
+	ErrLineTooLong = errors.New("input line too long")
+	// ErrInvalidUTF8 Введенная строка не является корректным UTF-8.
+	ErrInvalidUTF8 = errors.New("input is not valid UTF-8")
+)
+
+// PromptLine Выводит приглашение и читает строку целиком, какой бы длины она
+// ни была, без \r\n на конце. limit — предел в байтах (0 — DefaultMaxLine);
+// если строка длиннее, она дочитывается до конца и отбрасывается, а
+// возвращается ErrLineTooLong.
+func PromptLine(prompt string, echo bool, limit int) (string, error) {
+	hOut, _ := GetStdHandle(STD_OUTPUT_HANDLE)
+	hIn, _ := GetStdHandle(STD_INPUT_HANDLE)
+	return promptLine(hIn, hOut, prompt, echo, limit)
+}
+
+func promptLine(hIn, hOut Handle, prompt string, echo bool, limit int) (string, error) {
+	if limit <= 0 {
+		limit = DefaultMaxLine
+	}
+	if answer, ok, err := scriptedAnswer(hOut, prompt, !echo, 0); ok {
+		switch {
+		case err != nil:
+			return "", err
+		case len(answer) > limit:
+			return "", ErrLineTooLong
+		case !utf8.ValidString(answer):
+			return "", ErrInvalidUTF8
+		}
+		return answer, nil
+	}
+	if _, err := NewConsole(hOut).WriteString(prompt); err != nil {
+		return "", err
+	}
+
+	read := func(b []byte) (int, error) { return readFile(hIn, b) }
+
+	originalMode, err := getConsoleMode(hIn)
+	if err != nil {
+		// Не консоль — по байту, чтобы не забрать чужие строки
+		return collectLine(read, 1, limit)
+	}
+	if echo {
+		// Консоль в построчном режиме отдает не больше одной строки за чтение
+		return collectLine(read, 4096, limit)
+	}
+
+	guard, err := enterMode(hIn, originalMode, disableEcho(originalMode))
+	if err != nil {
+		return "", err
+	}
+	defer guard.Restore()
+	return collectLine(guard.Read, 4096, limit)
+}
+
+// collectLine Собирает строку до '\n' из чтений по chunk байт.
+func collectLine(read func([]byte) (int, error), chunk, limit int) (string, error) {
+	var line []byte
+	buf := make([]byte, chunk)
+	tooLong := false
+
+	for {
+		n, err := read(buf)
+		if err != nil {
+			return "", err
+		}
+		if n == 0 {
+			if len(line) == 0 && !tooLong {
+				return "", io.EOF
+			}
+			break
+		}
+
+		part := buf[:n]
+		end := bytes.IndexByte(part, '\n')
+		if end >= 0 {
+			part = part[:end+1]
+		}
+
+		// Запас в два байта под \r\n, которые потом отрежем
+		if !tooLong && len(line)+len(part) > limit+2 {
+			tooLong, line = true, nil
+		}
+		if !tooLong {
+			line = append(line, part...)
+		}
+		if end >= 0 {
+			break
+		}
+	}
+
+	if tooLong {
+		return "", ErrLineTooLong
+	}
+	line = line[:trimNewline(line, len(line))]
+	if len(line) > limit {
+		return "", ErrLineTooLong
+	}
+	if !utf8.Valid(line) {
+		return "", ErrInvalidUTF8
+	}
+	return string(line), nil
+}
+
+// drainLine Дочитывает и отбрасывает остаток строки до '\n' включительно.
+func drainLine(read func([]byte) (int, error), chunk int) error {
+	buf := make([]byte, chunk)
+	for {
+		n, err := read(buf)
+		if err != nil || n == 0 {
+			return err
+		}
+		if bytes.IndexByte(buf[:n], '\n') >= 0 {
+			return nil
+		}
+	}
+}
+
+// fitLine Обрезает строку, не поместившуюся в буфер, по границе символа UTF-8,
+// чтобы не вернуть половину многобайтного символа.
+func fitLine(buf []byte, count int) int {
+	if !truncated(buf, count) {
+		return count
+	}
+	for i := count - 1; i >= 0 && i >= count-utf8.UTFMax; i-- {
+		if utf8.RuneStart(buf[i]) {
+			if !utf8.FullRune(buf[i:count]) {
+				return i
+			}
+			break
+		}
+	}
+	return count
+}
@@ -0,0 +1,106 @@
+package console
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// chunkReader Отдает chunks по одному за чтение, как терминал в построчном
+// режиме; после них — конец файла. Чтение больше len(b) делится на части.
+func chunkReader(chunks ...string) func([]byte) (int, error) {
+	return func(b []byte) (int, error) {
+		for len(chunks) > 0 && chunks[0] == "" {
+			chunks = chunks[1:]
+		}
+		if len(chunks) == 0 {
+			return 0, nil
+		}
+		n := copy(b, chunks[0])
+		chunks[0] = chunks[0][n:]
+		return n, nil
+	}
+}
+
+func TestCollectLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		chunk  int
+		limit  int
+		want   string
+		err    error
+	}{
+		{"exact limit", []string{"abcde\n"}, 4096, 5, "abcde", nil},
+		{"limit with CRLF", []string{"abcde\r\n"}, 4096, 5, "abcde", nil},
+		{"limit+1 with LF", []string{"abcdef\n"}, 4096, 5, "", ErrLineTooLong},
+		{"limit+1 with CRLF", []string{"abcdef\r\n"}, 4096, 5, "", ErrLineTooLong},
+		{"limit+2 without newline", []string{"abcdefg"}, 4096, 5, "", ErrLineTooLong},
+		{"byte by byte", []string{"abcde\r\n"}, 1, 5, "abcde", nil},
+		{"byte by byte too long", []string{"abcdefgh\n"}, 1, 5, "", ErrLineTooLong},
+		{"split reads", []string{"ab", "cd", "e\n"}, 4096, 5, "abcde", nil},
+		{"multibyte within limit", []string{"жжж\n"}, 4096, 6, "жжж", nil},
+		{"multibyte over limit", []string{"жжж\n"}, 4096, 5, "", ErrLineTooLong},
+		{"multibyte split across reads", []string{"a\xd0", "\xb6\n"}, 4096, 10, "aж", nil},
+		{"invalid UTF-8", []string{"a\xff\n"}, 4096, 10, "", ErrInvalidUTF8},
+		{"truncated UTF-8 at EOF", []string{"a\xd0"}, 4096, 10, "", ErrInvalidUTF8},
+		{"last line without newline", []string{"abc"}, 4096, 10, "abc", nil},
+		{"empty line", []string{"\n"}, 4096, 10, "", nil},
+		{"EOF", nil, 4096, 10, "", io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collectLine(chunkReader(tt.chunks...), tt.chunk, tt.limit)
+			if got != tt.want || !errors.Is(err, tt.err) {
+				t.Errorf("collectLine = %q, %v; want %q, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestCollectLineTooLongKeepsNextLine(t *testing.T) {
+	// Побайтное чтение (не консоль): остаток длинной строки вычитывается,
+	// а следующая строка остается целой
+	read := chunkReader("abcdefgh\nok\n")
+	if _, err := collectLine(read, 1, 5); !errors.Is(err, ErrLineTooLong) {
+		t.Fatalf("first line: err = %v, want ErrLineTooLong", err)
+	}
+	if got, err := collectLine(read, 1, 5); got != "ok" || err != nil {
+		t.Errorf("second line = %q, %v; want %q, nil", got, err, "ok")
+	}
+}
+
+func TestCollectLineReadError(t *testing.T) {
+	boom := errors.New("boom")
+	read := func([]byte) (int, error) { return 0, boom }
+	if _, err := collectLine(read, 1, 5); err != boom {
+		t.Errorf("err = %v, want %v", err, boom)
+	}
+}
+
+func TestFitLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		buf   string // весь буфер
+		count int
+		want  int
+	}{
+		{"not full", "ab\xd0\x00", 3, 3},
+		{"ends with newline", "a\xd0\n", 3, 3},
+		{"full ASCII", "abcd", 4, 4},
+		{"full rune at end", "abж", 4, 4},
+		{"split 2-byte rune", "abc\xd0", 4, 3},
+		{"split 3-byte rune after 1 byte", "ab\xe2", 3, 2},
+		{"split 3-byte rune after 2 bytes", "a\xe2\x82", 3, 1},
+		{"split 4-byte rune after 3 bytes", "a\xf0\x9f\x98", 4, 1},
+		{"full 4-byte rune", "\xf0\x9f\x98\x80", 4, 4},
+		{"stray continuation bytes", "a\x82\x82", 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fitLine([]byte(tt.buf), tt.count); got != tt.want {
+				t.Errorf("fitLine(%q, %d) = %d, want %d", tt.buf, tt.count, got, tt.want)
+			}
+		})
+	}
+}
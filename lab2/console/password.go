@@ -0,0 +1,188 @@
+package console
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// DefaultMask Символ, который выводится вместо каждого символа пароля.
+const DefaultMask = '*'
+
+// ReadPassword Выводит приглашение и читает пароль, печатая mask на каждый
+// введенный символ (0 — ничего не печатать). Backspace удаляет последний
+// символ целиком, даже многобайтный, Ctrl+U — весь ввод.
+//
+// Результат — байты пароля в UTF-8, без перевода строки. Значение нигде не
+// копируется в строки и не выводится; после использования вызывающий должен
+// затереть его через Wipe.
+func ReadPassword(prompt string, mask rune) ([]byte, error) {
+	hIn, err := GetStdHandle(STD_INPUT_HANDLE)
+	if err != nil {
+		return nil, err
+	}
+	hOut, err := GetStdHandle(STD_OUTPUT_HANDLE)
+	if err != nil {
+		return nil, err
+	}
+	return readPassword(hIn, hOut, prompt, mask)
+}
+
+// Wipe Затирает буфер нулями.
+func Wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+func readPassword(hIn, hOut Handle, prompt string, mask rune) ([]byte, error) {
+	if answer, ok, err := scriptedAnswer(hOut, prompt, true, mask); ok {
+		if err != nil {
+			return nil, err
+		}
+		return []byte(answer), nil
+	}
+
+	out := NewConsole(hOut)
+	if _, err := out.WriteString(prompt); err != nil {
+		return nil, err
+	}
+
+	original, err := getConsoleMode(hIn)
+	if err != nil {
+		// Не консоль (пароль из pipe) — маскировать нечего
+		return readSecretLine(hIn)
+	}
+
+	guard, err := enterMode(hIn, original, makeRaw(original))
+	if err != nil {
+		return nil, err
+	}
+	defer guard.Restore()
+
+	keys := newKeyReader(guard.Read)
+	defer Wipe(keys.buf)
+
+	var echo, erase []byte
+	if mask != 0 {
+		echo = []byte(string(mask))
+		erase = []byte("\b \b")
+	}
+
+	secret := &secretBuffer{}
+	for {
+		key, err := keys.ReadKey()
+		if err != nil {
+			secret.wipe()
+			out.WriteString("\r\n")
+			return nil, err
+		}
+
+		switch key.Code {
+		case KeyEnter:
+			out.WriteString("\r\n")
+			return secret.bytes(), nil
+		case KeyCtrlC:
+			secret.wipe()
+			out.WriteString("^C\r\n")
+			return nil, ErrInterrupted
+		case KeyCtrlD:
+			if secret.len() == 0 {
+				out.WriteString("\r\n")
+				return nil, io.EOF
+			}
+		case KeyRune:
+			secret.appendRune(key.Rune)
+			out.Write(echo)
+		case KeyBackspace:
+			if secret.dropRune() {
+				out.Write(erase)
+			}
+		case KeyCtrlU:
+			for secret.dropRune() {
+				out.Write(erase)
+			}
+		}
+	}
+}
+
+// readSecretLine Читает строку из не-консоли в secretBuffer.
+func readSecretLine(h Handle) ([]byte, error) {
+	secret := &secretBuffer{}
+	b := make([]byte, 1)
+	for {
+		n, err := readFile(h, b)
+		if err != nil {
+			secret.wipe()
+			return nil, err
+		}
+		if n == 0 {
+			if secret.len() == 0 {
+				return nil, io.EOF
+			}
+			break
+		}
+		if b[0] == '\n' {
+			break
+		}
+		secret.appendByte(b[0])
+	}
+	b[0] = 0
+	if n := secret.len(); n > 0 && secret.buf[n-1] == '\r' {
+		secret.buf[n-1] = 0
+		secret.buf = secret.buf[:n-1]
+	}
+	return secret.bytes(), nil
+}
+
+// secretBuffer Буфер для секрета: при росте старый массив затирается,
+// чтобы в памяти не оставалось копий пароля.
+type secretBuffer struct {
+	buf []byte
+}
+
+func (s *secretBuffer) len() int {
+	return len(s.buf)
+}
+
+func (s *secretBuffer) grow(n int) {
+	if len(s.buf)+n <= cap(s.buf) {
+		return
+	}
+	bigger := make([]byte, len(s.buf), 2*cap(s.buf)+n+32)
+	copy(bigger, s.buf)
+	Wipe(s.buf[:cap(s.buf)])
+	s.buf = bigger
+}
+
+func (s *secretBuffer) appendByte(b byte) {
+	s.grow(1)
+	s.buf = append(s.buf, b)
+}
+
+func (s *secretBuffer) appendRune(r rune) {
+	s.grow(utf8.UTFMax)
+	s.buf = utf8.AppendRune(s.buf, r)
+}
+
+// dropRune Удаляет последний символ UTF-8 целиком.
+func (s *secretBuffer) dropRune() bool {
+	if len(s.buf) == 0 {
+		return false
+	}
+	_, size := utf8.DecodeLastRune(s.buf)
+	Wipe(s.buf[len(s.buf)-size:])
+	s.buf = s.buf[:len(s.buf)-size]
+	return true
+}
+
+// bytes Отдает накопленные байты. Буфер переходит к вызывающему.
+func (s *secretBuffer) bytes() []byte {
+	b := s.buf
+	s.buf = nil
+	return b
+}
+
+func (s *secretBuffer) wipe() {
+	Wipe(s.buf[:cap(s.buf)])
+	s.buf = nil
+}
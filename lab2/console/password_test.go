@@ -0,0 +1,45 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSecretBufferDropRune(t *testing.T) {
+	s := &secretBuffer{}
+	for _, r := range "aж€😀" {
+		s.appendRune(r)
+	}
+	want := []string{"aж€", "aж", "a", ""}
+	for _, w := range want {
+		if !s.dropRune() {
+			t.Fatalf("dropRune() = false before %q", w)
+		}
+		if string(s.buf) != w {
+			t.Fatalf("after dropRune: %q, want %q", s.buf, w)
+		}
+		// Удаленные байты затерты, а не просто отрезаны
+		if tail := s.buf[len(s.buf):cap(s.buf)]; bytes.IndexFunc(tail, func(r rune) bool { return r != 0 }) >= 0 {
+			t.Fatalf("dropped bytes were not wiped: % x", tail)
+		}
+	}
+	if s.dropRune() {
+		t.Error("dropRune() = true on empty buffer")
+	}
+}
+
+func TestSecretBufferGrowWipesOldArray(t *testing.T) {
+	s := &secretBuffer{}
+	s.appendByte('x')
+	old := s.buf[:cap(s.buf)]
+	n := len(old) + 1
+	for s.len() < n {
+		s.appendByte('x')
+	}
+	if bytes.IndexByte(old, 'x') >= 0 {
+		t.Errorf("old array still holds the secret: %q", old)
+	}
+	if got := s.bytes(); !bytes.Equal(got, bytes.Repeat([]byte("x"), n)) || s.buf != nil {
+		t.Errorf("bytes() = %q, buffer not handed over", got)
+	}
+}
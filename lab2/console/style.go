@@ -0,0 +1,198 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Color Цвет текста или фона из 8 стандартных цветов терминала.
+type Color int
+
+const (
+	ColorDefault Color = iota
+	Black
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+)
+
+// Style Оформление текста. Нулевое значение — без оформления.
+type Style struct {
+	Fg, Bg    Color
+	Bold      bool
+	Underline bool
+}
+
+// Оформление, общее для меню и сообщений всех программ.
+var (
+	StyleTitle    = Style{Fg: Cyan, Bold: true}
+	StyleError    = Style{Fg: Red, Bold: true}
+	StyleWarning  = Style{Fg: Yellow}
+	StyleSuccess  = Style{Fg: Green}
+	StyleHint     = Style{Underline: true}
+	StyleSelected = Style{Fg: Black, Bg: Cyan}
+)
+
+// sgr Последовательность SGR, включающая стиль; пустая для нулевого стиля.
+func (s Style) sgr() string {
+	var codes []string
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Underline {
+		codes = append(codes, "4")
+	}
+	if s.Fg != ColorDefault {
+		codes = append(codes, strconv.Itoa(29+int(s.Fg)))
+	}
+	if s.Bg != ColorDefault {
+		codes = append(codes, strconv.Itoa(39+int(s.Bg)))
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+const sgrReset = "\x1b[0m"
+
+// Terminal Понимает ли вывод управляющие последовательности (курсор, очистка
+// экрана): вывод является терминалом и TERM не dumb. NO_COLOR на это не влияет.
+func (c *Console) Terminal() bool {
+	if c.term == ansiUnknown {
+		c.term = ansiOff
+		if detectTerminal(c.h) {
+			c.term = ansiOn
+		}
+	}
+	return c.term == ansiOn
+}
+
+// SetTerminal Принудительно включает или выключает управляющие
+// последовательности; вместе с ними — и цвет.
+func (c *Console) SetTerminal(on bool) {
+	c.term = ansiOff
+	if on {
+		c.term = ansiOn
+	}
+}
+
+// Color Выводится ли цвет (SGR). По умолчанию — если вывод является
+// терминалом и переменная NO_COLOR не задана.
+func (c *Console) Color() bool {
+	if c.color == ansiUnknown {
+		c.color = ansiOff
+		if os.Getenv("NO_COLOR") == "" {
+			c.color = ansiOn
+		}
+	}
+	return c.color == ansiOn && c.Terminal()
+}
+
+// SetColor Принудительно включает или выключает цвет. На выводе, который не
+// является терминалом, цвета все равно не будет.
+func (c *Console) SetColor(on bool) {
+	c.color = ansiOff
+	if on {
+		c.color = ansiOn
+	}
+}
+
+func detectTerminal(h Handle) bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return supportsANSI(h)
+}
+
+// Print Выводит текст в стиле st. Без цвета (вывод перенаправлен, NO_COLOR)
+// стиль не применяется, а последовательности, уже вписанные в text, вырезаются.
+func (c *Console) Print(st Style, text string) (int, error) {
+	if !c.Color() {
+		return c.WriteString(StripANSI(text))
+	}
+	if on := st.sgr(); on != "" {
+		text = on + text + sgrReset
+	}
+	return c.WriteString(text)
+}
+
+// Printf То же, что Print, с форматированием.
+func (c *Console) Printf(st Style, format string, args ...any) (int, error) {
+	return c.Print(st, fmt.Sprintf(format, args...))
+}
+
+// MoveTo Ставит курсор в строку row и столбец col (с 1). Если вывод не
+// терминал, ничего не делает, как и остальные функции управления курсором.
+func (c *Console) MoveTo(row, col int) error {
+	return c.escape(fmt.Sprintf("\x1b[%d;%dH", row, col))
+}
+
+// ClearLine Стирает текущую строку и ставит курсор в ее начало.
+func (c *Console) ClearLine() error {
+	return c.escape("\r\x1b[2K")
+}
+
+// ClearScreen Очищает экран и ставит курсор в левый верхний угол.
+func (c *Console) ClearScreen() error {
+	return c.escape("\x1b[2J\x1b[H")
+}
+
+func (c *Console) escape(seq string) error {
+	if !c.Terminal() {
+		return nil
+	}
+	_, err := c.WriteString(seq)
+	return err
+}
+
+// PrintStyled Выводит строку в стиле st, аналог PrintMsg.
+func PrintStyled(hOut Handle, st Style, msg string) bool {
+	_, err := NewConsole(hOut).Print(st, msg)
+	return err == nil
+}
+
+// StripANSI Убирает из строки управляющие последовательности ESC: CSI
+// (ESC [ ... буква), OSC (ESC ] ... BEL или ESC \) и двухсимвольные ESC X.
+func StripANSI(s string) string {
+	if strings.IndexByte(s, '\x1b') < 0 {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			break
+		}
+		switch s[i+1] {
+		case '[':
+			// Параметры и промежуточные байты, затем финальный байт 0x40–0x7E
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			i = j
+		case ']':
+			j := i + 2
+			for j < len(s) && s[j] != '\a' && !(s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\') {
+				j++
+			}
+			if j < len(s) && s[j] == '\x1b' {
+				j++
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package console
+
+import "testing"
+
+func TestStyleSGR(t *testing.T) {
+	tests := []struct {
+		st   Style
+		want string
+	}{
+		{Style{}, ""},
+		{Style{Bold: true}, "\x1b[1m"},
+		{Style{Underline: true}, "\x1b[4m"},
+		{Style{Fg: Black}, "\x1b[30m"},
+		{Style{Fg: White}, "\x1b[37m"},
+		{Style{Bg: Black}, "\x1b[40m"},
+		{Style{Bg: White}, "\x1b[47m"},
+		{StyleTitle, "\x1b[1;36m"},
+		{StyleSelected, "\x1b[30;46m"},
+		{Style{Fg: Red, Bg: Yellow, Bold: true, Underline: true}, "\x1b[1;4;31;43m"},
+	}
+	for _, tt := range tests {
+		if got := tt.st.sgr(); got != tt.want {
+			t.Errorf("%+v.sgr() = %q, want %q", tt.st, got, tt.want)
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "hello, мир", "hello, мир"},
+		{"SGR", "\x1b[1;31mred\x1b[0m text", "red text"},
+		{"cursor movement", "a\x1b[2Ab\x1b[10;20Hc\x1b[2K", "abc"},
+		{"private mode", "\x1b[?25lhidden\x1b[?25h", "hidden"},
+		{"OSC with BEL", "\x1b]0;window title\atext", "text"},
+		{"OSC with ST", "\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"two-byte escape", "\x1b7saved\x1b8", "saved"},
+		{"unterminated CSI", "text\x1b[12", "text"},
+		{"unterminated OSC", "text\x1b]0;title", "text"},
+		{"trailing ESC", "text\x1b", "text"},
+		{"keeps other controls", "a\tb\r\n", "a\tb\r\n"},
+		{"round trip", StyleError.sgr() + "error" + sgrReset, "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripANSI(tt.in); got != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package console
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrCanceled Пользователь отказался от выбора (Esc или q в меню).
+var ErrCanceled = errors.New("canceled")
+
+// UI Виджеты ввода поверх консоли: меню, подтверждение, проверяемый ввод и
+// пароль с подтверждением. Если ввод не консоль, все виджеты читают ответы
+// построчно (меню — номер пункта), поэтому их можно прогнать по сценарию из
+// файла или канала; так же они работают с ответами из SetAnswers. Конец
+// ввода возвращается как io.EOF, а не зацикливает повтор.
+type UI struct {
+	hIn Handle
+	out *Console
+}
+
+// NewUI Виджеты на стандартных дескрипторах.
+func NewUI() *UI {
+	hIn, _ := GetStdHandle(STD_INPUT_HANDLE)
+	hOut, _ := GetStdHandle(STD_OUTPUT_HANDLE)
+	return newUI(hIn, hOut)
+}
+
+func newUI(hIn, hOut Handle) *UI {
+	return &UI{hIn: hIn, out: NewConsole(hOut)}
+}
+
+// Menu Показывает пункты и возвращает индекс выбранного (с 0). В консоли выбор
+// стрелками (или цифрой), Enter — подтвердить, Esc/q — ErrCanceled.
+// Без консоли печатает нумерованный список и спрашивает номер.
+func (u *UI) Menu(title string, items []string) (int, error) {
+	if len(items) == 0 {
+		return 0, errors.New("menu has no items")
+	}
+	original, err := getConsoleMode(u.hIn)
+	if err != nil || script != nil || !u.out.Terminal() {
+		return u.numberedMenu(title, items)
+	}
+
+	guard, err := enterMode(u.hIn, original, makeRaw(original))
+	if err != nil {
+		return 0, err
+	}
+	defer guard.Restore()
+
+	u.out.Print(StyleTitle, title+"\r\n")
+	u.out.WriteString("\x1b[?25l") // курсор прячем на время выбора
+	defer u.out.WriteString("\x1b[?25h")
+
+	current := 0
+	u.drawMenu(items, current, false)
+
+	keys := newKeyReader(guard.Read)
+	for {
+		key, err := keys.ReadKey()
+		if err != nil {
+			return 0, err
+		}
+		switch key.Code {
+		case KeyUp:
+			current = (current + len(items) - 1) % len(items)
+		case KeyDown, KeyTab:
+			current = (current + 1) % len(items)
+		case KeyHome:
+			current = 0
+		case KeyEnd:
+			current = len(items) - 1
+		case KeyEnter:
+			return current, nil
+		case KeyCtrlC:
+			return 0, ErrInterrupted
+		case KeyEscape:
+			return 0, ErrCanceled
+		case KeyRune:
+			if key.Rune == 'q' {
+				return 0, ErrCanceled
+			}
+			if n := int(key.Rune - '0'); n >= 1 && n <= 9 && n <= len(items) {
+				u.drawMenu(items, n-1, true)
+				return n - 1, nil
+			}
+			continue
+		default:
+			continue
+		}
+		u.drawMenu(items, current, true)
+	}
+}
+
+// drawMenu Рисует пункты меню; redraw — поверх уже нарисованных.
+func (u *UI) drawMenu(items []string, current int, redraw bool) {
+	if redraw {
+		fmt.Fprintf(u.out, "\x1b[%dA", len(items))
+	}
+	for i, item := range items {
+		u.out.WriteString("\r\x1b[2K")
+		if i == current {
+			u.out.Print(StyleSelected, "> "+item)
+		} else {
+			u.out.WriteString("  " + item)
+		}
+		u.out.WriteString("\r\n")
+	}
+}
+
+func (u *UI) numberedMenu(title string, items []string) (int, error) {
+	u.out.Print(StyleTitle, title+"\r\n")
+	for i, item := range items {
+		fmt.Fprintf(u.out, "%d. %s\r\n", i+1, item)
+	}
+	n, err := u.Number(fmt.Sprintf("Choose [1-%d]: ", len(items)), 1, len(items))
+	return n - 1, err
+}
+
+// Confirm Вопрос да/нет. Пустой ответ — def; понимает y/yes/n/no и д/да/н/нет.
+func (u *UI) Confirm(question string, def bool) (bool, error) {
+	hint := " [y/N]: "
+	if def {
+		hint = " [Y/n]: "
+	}
+	for {
+		answer, err := u.readLine(question + hint)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return def, nil
+		case "y", "yes", "д", "да":
+			return true, nil
+		case "n", "no", "н", "нет":
+			return false, nil
+		}
+		u.out.Print(StyleError, "Please answer yes or no.\r\n")
+	}
+}
+
+// Input Читает строку и проверяет ее validate (nil — без проверки). При ошибке
+// проверки печатает ее и спрашивает снова.
+func (u *UI) Input(prompt string, validate func(string) error) (string, error) {
+	for {
+		answer, err := u.readLine(prompt)
+		switch {
+		case errors.Is(err, ErrLineTooLong), errors.Is(err, ErrInvalidUTF8):
+			// Остаток строки уже вычитан, можно спрашивать снова
+		case err != nil:
+			return "", err
+		case validate == nil:
+			return answer, nil
+		default:
+			if err = validate(answer); err == nil {
+				return answer, nil
+			}
+		}
+		u.out.Print(StyleError, "Invalid input: "+err.Error()+"\r\n")
+	}
+}
+
+// Number Читает целое число в диапазоне [min, max] с повтором при ошибке.
+func (u *UI) Number(prompt string, min, max int) (int, error) {
+	var n int
+	_, err := u.Input(prompt, func(s string) error {
+		v, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return errors.New("not a number")
+		}
+		if v < min || v > max {
+			return fmt.Errorf("number must be from %d to %d", min, max)
+		}
+		n = v
+		return nil
+	})
+	return n, err
+}
+
+// NewPassword Пароль с подтверждением: спрашивает дважды, пока значения не
+// совпадут. Пустой пароль не принимается. Промежуточные значения затираются,
+// результат вызывающий затирает сам через Wipe.
+func (u *UI) NewPassword(prompt, confirm string, mask rune) ([]byte, error) {
+	for {
+		first, err := readPassword(u.hIn, u.out.Handle(), prompt, mask)
+		if err != nil {
+			return nil, err
+		}
+		if len(first) == 0 {
+			u.out.Print(StyleError, "Password must not be empty.\r\n")
+			continue
+		}
+		second, err := readPassword(u.hIn, u.out.Handle(), confirm, mask)
+		if err != nil {
+			Wipe(first)
+			return nil, err
+		}
+		match := subtle.ConstantTimeCompare(first, second) == 1
+		Wipe(second)
+		if match {
+			return first, nil
+		}
+		Wipe(first)
+		u.out.Print(StyleError, "Passwords do not match, try again.\r\n")
+	}
+}
+
+func (u *UI) readLine(prompt string) (string, error) {
+	return promptLine(u.hIn, u.out.Handle(), prompt, true, 0)
+}
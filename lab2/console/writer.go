@@ -0,0 +1,66 @@
+package console
+
+import "io"
+
+// Console Вывод в дескриптор консоли как io.Writer и io.StringWriter.
+// В отличие от одного writeFile, Write пишет все байты: повторяет запись после
+// частичной, а также после EINTR и EAGAIN (для неблокирующего fd).
+// Подходит для fmt.Fprintf. Оформление текста — в style.go.
+type Console struct {
+	h     Handle
+	term  ansiMode // управляющие последовательности
+	color ansiMode // цвет (SGR)
+}
+
+// ansiMode Выводить ли ANSI-последовательности; определяется при первом выводе в стиле.
+type ansiMode int8
+
+const (
+	ansiUnknown ansiMode = iota
+	ansiOn
+	ansiOff
+)
+
+// NewConsole Оборачивает дескриптор вывода.
+func NewConsole(h Handle) *Console {
+	return &Console{h: h}
+}
+
+// Stdout Консоль для STD_OUTPUT_HANDLE.
+func Stdout() (*Console, error) {
+	h, err := GetStdHandle(STD_OUTPUT_HANDLE)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsole(h), nil
+}
+
+// Handle Дескриптор, в который пишет Console.
+func (c *Console) Handle() Handle {
+	return c.h
+}
+
+// Write Пишет p целиком. Если записано меньше len(p), возвращает ошибку.
+func (c *Console) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		n, err := writeFile(c.h, p[written:])
+		written += n
+		if err != nil {
+			if retryWrite(c.h, err) {
+				continue
+			}
+			return written, err
+		}
+		if n == 0 {
+			// Запись без ошибки и без прогресса — не крутимся вечно
+			return written, io.ErrShortWrite
+		}
+	}
+	return written, nil
+}
+
+// WriteString То же, что Write, для строки.
+func (c *Console) WriteString(s string) (int, error) {
+	return c.Write([]byte(s))
+}
@@ -1,105 +1,44 @@
 package main
 
 import (
+	"crypto/subtle"
+	"errors"
+	"flag"
 	"fmt"
-	"syscall"
-	"unsafe"
-)
+	"os"
+	"strings"
+	"unicode/utf8"
 
-const (
-	STD_INPUT_HANDLE  = 0xFFFFFFF6 // -10
-	STD_OUTPUT_HANDLE = 0xFFFFFFF5 // -11
-	ENABLE_ECHO_INPUT = 0x0004
+	"lab2/console"
 )
 
-// kernel32 DLL для функций работы с режимом консоли (в syscall их может не быть напрямую)
-var (
-	modkernel32        = syscall.NewLazyDLL("kernel32.dll")
-	procGetConsoleMode = modkernel32.NewProc("GetConsoleMode")
-	procSetConsoleMode = modkernel32.NewProc("SetConsoleMode")
-)
+var answers string
 
-// PrintMsg Выводит одну строку в дескриптор.
-func PrintMsg(hOut syscall.Handle, msg string) bool {
-	b := []byte(msg)
-	var written uint32
-	err := syscall.WriteFile(hOut, b, &written, nil)
-	return err == nil
+func init() {
+	flag.StringVar(&answers, "answers", "", "Ответы на вопросы: файл, по одному на строку, или - (стандартный ввод); без флага — из $"+console.AnswersEnv)
 }
 
-// PrintStrings Выводит список строк переменной длины.
-func PrintStrings(hOut syscall.Handle, msg ...string) bool {
-	for _, m := range msg {
-		if !PrintMsg(hOut, m) {
-			return false
-		}
-	}
-	return true
-}
-
-// ConsolePrompt Выводит приглашение и читает ответ.
-func ConsolePrompt(pPromptMsg string, pResponse []byte, echo bool) (int, bool) {
-	hOut, _ := syscall.GetStdHandle(STD_OUTPUT_HANDLE)
-	hIn, _ := syscall.GetStdHandle(STD_INPUT_HANDLE)
-
-	if !PrintMsg(hOut, pPromptMsg) {
-		return 0, false
-	}
-
-	// настройка режима эха
-	var originalMode uint32
-	r1, _, _ := procGetConsoleMode.Call(uintptr(hIn), uintptr(unsafe.Pointer(&originalMode)))
-	if r1 == 0 {
-		return 0, false // ошибка получения режима
-	}
-
-	currentMode := originalMode
-	if !echo {
-		// Отключаем ECHO_INPUT
-		currentMode &^= ENABLE_ECHO_INPUT
-		procSetConsoleMode.Call(uintptr(hIn), uintptr(currentMode))
-	}
-
-	// Читаем ввод (ReadFile)
-	var read uint32
-	err := syscall.ReadFile(hIn, pResponse, &read, nil)
-
-	// Восстанавливаем режим сразу после чтения
-	if !echo {
-		procSetConsoleMode.Call(uintptr(hIn), uintptr(originalMode))
-	}
-
-	if err != nil {
-		return 0, false
-	}
-
-	// Убираем символы перевода строки (\r\n) из конца, если они есть
-	count := int(read)
-	if count > 0 && pResponse[count-1] == '\n' {
-		count--
-	}
-	if count > 0 && pResponse[count-1] == '\r' {
-		count--
+func main() {
+	flag.Parse()
+	if err := console.LoadAnswers(answers); err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading answers:", err)
+		os.Exit(2)
 	}
 
-	return count, true
-}
-
-func main() {
 	// Получаем стандартный дескриптор вывода для тестов
-	hOut, err := syscall.GetStdHandle(STD_OUTPUT_HANDLE)
+	hOut, err := console.GetStdHandle(console.STD_OUTPUT_HANDLE)
 	if err != nil {
 		fmt.Println("Error getting StdOut handle:", err)
 		return
 	}
 
 	// ТЕСТ 1: PrintMsg
-	PrintMsg(hOut, "--- Test 1: PrintMsg ---\r\n")
-	PrintMsg(hOut, "Hello from single message!\r\n\r\n")
+	console.PrintStyled(hOut, console.StyleTitle, "--- Test 1: PrintMsg ---\r\n")
+	console.PrintMsg(hOut, "Hello from single message!\r\n\r\n")
 
 	// ТЕСТ 2: PrintStrings (аналог va_list)
-	PrintMsg(hOut, "--- Test 2: PrintStrings ---\r\n")
-	PrintStrings(hOut,
+	console.PrintStyled(hOut, console.StyleTitle, "--- Test 2: PrintStrings ---\r\n")
+	console.PrintStrings(hOut,
 		"Line 1: This is ",
 		"constructed from ",
 		"multiple arguments.\r\n",
@@ -107,34 +46,78 @@ func main() {
 	)
 
 	// ТЕСТ 3: ConsolePrompt с Эхо (обычный ввод)
-	PrintMsg(hOut, "--- Test 3: ConsolePrompt (Echo ON) ---\r\n")
+	console.PrintStyled(hOut, console.StyleTitle, "--- Test 3: ConsolePrompt (Echo ON) ---\r\n")
 	buffer := make([]byte, 256) // MaxTchar = 256
 
-	lenRead, ok := ConsolePrompt("Enter your name: ", buffer, true)
+	lenRead, ok := console.ConsolePrompt("Enter your name: ", buffer, true)
 	if ok {
 		name := string(buffer[:lenRead])
-		PrintStrings(hOut, "Hello, ", name, "!\r\n\r\n")
+		console.PrintStrings(hOut, "Hello, ", name, "!\r\n\r\n")
 	} else {
-		PrintMsg(hOut, "Error reading name.\r\n")
+		console.PrintStyled(hOut, console.StyleError, "Error reading name.\r\n")
 	}
 
-	// ТЕСТ 4: ConsolePrompt БЕЗ Эхо (пароль)
-	PrintMsg(hOut, "--- Test 4: ConsolePrompt (Echo OFF / Password) ---\r\n")
+	// ТЕСТ 3b: PromptLine — строка любой длины, с проверкой UTF-8
+	console.PrintStyled(hOut, console.StyleTitle, "--- Test 3b: PromptLine (up to 1024 bytes) ---\r\n")
+	about, err := console.PromptLine("Tell about yourself: ", true, 1024)
+	switch {
+	case errors.Is(err, console.ErrLineTooLong):
+		console.PrintStyled(hOut, console.StyleWarning, "Too long, skipped.\r\n\r\n")
+	case err != nil:
+		console.PrintStyled(hOut, console.StyleError, "Error: "+err.Error()+"\r\n\r\n")
+	default:
+		fmt.Fprintf(console.NewConsole(hOut), "Got %d characters.\r\n\r\n", utf8.RuneCountInString(about))
+	}
 
-	// Ввод пароля
-	lenPass1, _ := ConsolePrompt("Create password: ", buffer, false)
-	pass1 := string(buffer[:lenPass1])
+	// ТЕСТ 4: ReadPassword (ввод под маской)
+	console.PrintStyled(hOut, console.StyleTitle, "--- Test 4: ReadPassword (masked) ---\r\n")
 
-	// Подтверждение пароля
-	buffer2 := make([]byte, 256)
-	lenPass2, _ := ConsolePrompt("Confirm password: ", buffer2, false)
-	pass2 := string(buffer2[:lenPass2])
+	pass1, err1 := console.ReadPassword("Create password: ", console.DefaultMask)
+	pass2, err2 := console.ReadPassword("Confirm password: ", console.DefaultMask)
 
-	// Проверка
-	if pass1 == pass2 {
-		PrintStrings(hOut, "Success: Passwords match!\r\n")
-	} else {
-		PrintStrings(hOut, "Error: Passwords do not match.\r\n")
-		PrintStrings(hOut, "Got: '", pass1, "' and '", pass2, "'\r\n")
+	// Проверка: сами пароли не выводим
+	switch {
+	case err1 != nil || err2 != nil:
+		console.PrintStyled(hOut, console.StyleError, "Error reading password.\r\n")
+	case subtle.ConstantTimeCompare(pass1, pass2) == 1:
+		console.PrintStyled(hOut, console.StyleSuccess, "Success: Passwords match!\r\n")
+	default:
+		console.PrintStyled(hOut, console.StyleError, "Error: Passwords do not match.\r\n")
+	}
+	console.Wipe(pass1)
+	console.Wipe(pass2)
+
+	// ТЕСТ 5: LineEditor (история, стрелки, Tab)
+	console.PrintStyled(hOut, console.StyleTitle, "\r\n--- Test 5: LineEditor (empty line or Ctrl+D to stop) ---\r\n")
+
+	commands := []string{"help", "history", "hello", "quit"}
+	editor := console.NewLineEditor()
+	editor.Complete = func(line string) []string {
+		var options []string
+		for _, c := range commands {
+			if strings.HasPrefix(c, line) {
+				options = append(options, c)
+			}
+		}
+		return options
+	}
+	for {
+		line, err := editor.ReadLine("> ")
+		if err != nil || line == "" {
+			break
+		}
+		console.PrintStrings(hOut, "You typed: ", line, "\r\n")
+	}
+
+	// ТЕСТ 6: виджеты (меню, подтверждение)
+	console.PrintStyled(hOut, console.StyleTitle, "\r\n--- Test 6: Widgets ---\r\n")
+	ui := console.NewUI()
+	choice, err := ui.Menu("Pick a color:", []string{"Red", "Green", "Blue"})
+	if err != nil {
+		console.PrintStyled(hOut, console.StyleError, "Menu: "+err.Error()+"\r\n")
+		return
+	}
+	if ok, _ := ui.Confirm(fmt.Sprintf("Use choice #%d?", choice+1), true); ok {
+		console.PrintStyled(hOut, console.StyleSuccess, "Confirmed.\r\n")
 	}
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// copyBufferSize Размер буфера для обычного цикла чтения/записи.
+const copyBufferSize = 256 * 1024
+
+// copyMethod Способ копирования, выбранный для пары файлов.
+type copyMethod int
+
+const (
+	methodBuffer        copyMethod = iota // read/write через буфер
+	methodCopyFileRange                   // copy_file_range: файл -> файл
+	methodSplice                          // splice: одна из сторон — канал
+	methodSendfile                        // sendfile: файл -> файл или сокет
+)
+
+// CatFile Копирует src в dst. Если обе стороны — файлы ОС (вывод без оформления),
+// данные по возможности передаются ядром без копирования в память процесса
+// (copy_file_range, splice, sendfile), иначе — циклом с большим буфером.
+func CatFile(src io.Reader, dst io.Writer) error {
+	if in, ok := src.(*os.File); ok {
+		if out, ok := dst.(output); ok {
+			if f, ok := out.w.(*os.File); ok {
+				if handled, err := fastCopy(f, in); handled {
+					return err
+				}
+			}
+		}
+	}
+	return copyBuffer(dst, src, make([]byte, copyBufferSize))
+}
+
+// copyBuffer Обычный цикл read/write. В отличие от io.CopyBuffer не ищет
+// WriterTo/ReaderFrom, так что размер буфера соблюдается всегда.
+func copyBuffer(dst io.Writer, src io.Reader, buf []byte) error {
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
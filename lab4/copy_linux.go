@@ -0,0 +1,115 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// chunkSize Сколько байт передавать ядру за один вызов.
+const chunkSize = 1 << 30
+
+// pipeSize Желаемый размер буфера канала для splice (по умолчанию в Linux 64 КБ).
+const pipeSize = 1 << 20
+
+// fastCopy Копирует src в dst средствами ядра. handled == false — ни один
+// способ не подошел и ничего не скопировано, нужен обычный цикл.
+func fastCopy(dst, src *os.File) (handled bool, err error) {
+	dfd, sfd := int(dst.Fd()), int(src.Fd())
+	for _, method := range chooseMethods(dfd, sfd) {
+		written, err := kernelCopy(method, dfd, sfd)
+		if written == 0 && unsupported(err) {
+			// Например, copy_file_range между разными ФС на старом ядре — пробуем дальше
+			continue
+		}
+		return true, classify(err)
+	}
+	return false, nil
+}
+
+// chooseMethods Подходящие способы для пары дескрипторов, по убыванию выгоды.
+// На терминал и /dev/null пишем обычным циклом.
+func chooseMethods(dfd, sfd int) []copyMethod {
+	var dst, src unix.Stat_t
+	if unix.Fstat(dfd, &dst) != nil || unix.Fstat(sfd, &src) != nil {
+		return nil
+	}
+	dstType, srcType := dst.Mode&unix.S_IFMT, src.Mode&unix.S_IFMT
+
+	var methods []copyMethod
+	switch {
+	case dstType == unix.S_IFREG && srcType == unix.S_IFREG:
+		methods = append(methods, methodCopyFileRange, methodSendfile)
+	case dstType == unix.S_IFIFO || srcType == unix.S_IFIFO:
+		methods = append(methods, methodSplice)
+	case dstType == unix.S_IFSOCK && srcType == unix.S_IFREG:
+		methods = append(methods, methodSendfile)
+	}
+	return methods
+}
+
+// kernelCopy Копирует до конца src одним способом. Смещения не передаются,
+// поэтому используются и сдвигаются текущие позиции файлов.
+func kernelCopy(method copyMethod, dfd, sfd int) (int64, error) {
+	if method == methodSplice {
+		// Больший буфер канала — меньше переключений между cat и читателем.
+		// Не вышло (приемник не канал, нет прав на такой размер) — не страшно.
+		unix.FcntlInt(uintptr(dfd), unix.F_SETPIPE_SZ, pipeSize)
+	}
+	var total int64
+	for {
+		var n int64
+		var err error
+		switch method {
+		case methodCopyFileRange:
+			var m int
+			m, err = unix.CopyFileRange(sfd, nil, dfd, nil, chunkSize, 0)
+			n = int64(m)
+		case methodSplice:
+			var m int64
+			m, err = splice(sfd, dfd)
+			n = m
+		case methodSendfile:
+			var m int
+			m, err = unix.Sendfile(dfd, sfd, nil, chunkSize)
+			n = int64(m)
+		}
+		if err == unix.EINTR {
+			continue
+		}
+		if n > 0 {
+			total += n
+		}
+		if err != nil || n <= 0 {
+			return total, err
+		}
+	}
+}
+
+// splice Результат unix.Splice разного типа на разных архитектурах.
+func splice(sfd, dfd int) (int64, error) {
+	n, err := unix.Splice(sfd, nil, dfd, nil, chunkSize, unix.SPLICE_F_MOVE|unix.SPLICE_F_MORE)
+	return int64(n), err
+}
+
+// unsupported Ошибки, означающие «этот способ не для этой пары файлов».
+func unsupported(err error) bool {
+	return errors.Is(err, unix.EINVAL) || errors.Is(err, unix.EXDEV) ||
+		errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EOPNOTSUPP) ||
+		errors.Is(err, unix.EBADF) || errors.Is(err, unix.EPERM)
+}
+
+// classify Ошибки переполнения и закрытого канала относятся к выводу.
+func classify(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, unix.EPIPE), errors.Is(err, unix.ENOSPC),
+		errors.Is(err, unix.EDQUOT), errors.Is(err, unix.EFBIG):
+		return &writeError{err}
+	}
+	return err
+}
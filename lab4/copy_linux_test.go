@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const benchSize = 64 << 20
+
+// benchSource Файл из benchSize байт, открытый заново для каждой итерации.
+func benchSource(b *testing.B) string {
+	b.Helper()
+	path := filepath.Join(b.TempDir(), "src")
+	data := make([]byte, 1<<20)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for written := 0; written < benchSize; written += len(data) {
+		if _, err := f.Write(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+	f.Close()
+	return path
+}
+
+// benchTarget Открывает приемник: /dev/null, канал с читателем или файл.
+// Возвращает функцию закрытия, дожидающуюся читателя канала.
+func benchTarget(b *testing.B, kind string) (*os.File, func()) {
+	b.Helper()
+	switch kind {
+	case "devnull":
+		f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		return f, func() { f.Close() }
+	case "pipe":
+		r, w, err := os.Pipe()
+		if err != nil {
+			b.Fatal(err)
+		}
+		done := make(chan struct{})
+		go func() {
+			io.Copy(io.Discard, r)
+			r.Close()
+			close(done)
+		}()
+		return w, func() { w.Close(); <-done }
+	default:
+		f, err := os.Create(filepath.Join(b.TempDir(), "dst"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		return f, func() { f.Close() }
+	}
+}
+
+// methodNames Имена способов для подтестов.
+var methodNames = map[copyMethod]string{
+	methodBuffer:        "buffer",
+	methodCopyFileRange: "copy_file_range",
+	methodSplice:        "splice",
+	methodSendfile:      "sendfile",
+}
+
+// BenchmarkCopy Пропускная способность для каждого приемника: "cat" — то, что
+// реально делает CatFile, "buffer" — обычный цикл для сравнения, а затем
+// каждый способ, который chooseMethods допускает для этого приемника.
+func BenchmarkCopy(b *testing.B) {
+	src := benchSource(b)
+
+	for _, target := range []string{"devnull", "pipe", "file"} {
+		probe, closeProbe := benchTarget(b, target)
+		in, err := os.Open(src)
+		if err != nil {
+			b.Fatal(err)
+		}
+		candidates := append([]copyMethod{methodBuffer}, chooseMethods(int(probe.Fd()), int(in.Fd()))...)
+		in.Close()
+		closeProbe()
+
+		b.Run(target+"/cat", func(b *testing.B) {
+			benchCopy(b, src, target, func(dst, in *os.File) (int64, error) {
+				err := CatFile(in, output{dst})
+				return benchSize, err
+			})
+		})
+		for _, method := range candidates {
+			b.Run(target+"/"+methodNames[method], func(b *testing.B) {
+				benchCopy(b, src, target, func(dst, in *os.File) (int64, error) {
+					if method == methodBuffer {
+						return benchSize, copyBuffer(output{dst}, in, make([]byte, copyBufferSize))
+					}
+					return kernelCopy(method, int(dst.Fd()), int(in.Fd()))
+				})
+			})
+		}
+	}
+}
+
+func benchCopy(b *testing.B, src, target string, copyFn func(dst, in *os.File) (int64, error)) {
+	dst, closeDst := benchTarget(b, target)
+	defer closeDst()
+	b.SetBytes(benchSize)
+
+	for i := 0; i < b.N; i++ {
+		in, err := os.Open(src)
+		if err != nil {
+			b.Fatal(err)
+		}
+		n, err := copyFn(dst, in)
+		in.Close()
+		if err != nil || n != benchSize {
+			b.Fatalf("copied %d of %d bytes: %v", n, benchSize, err)
+		}
+	}
+}
+
+// TestCatFile CatFile в файл и в канал (там, где работает быстрый путь) и в
+// /dev/null (обычный цикл) — выбор способа и побайтное совпадение результата.
+func TestCatFile(t *testing.T) {
+	dir := t.TempDir()
+	data := make([]byte, 3<<20+12345) // не кратно ни буферу, ни странице
+	for i := range data {
+		data[i] = byte(i*31 + i>>9)
+	}
+	src := filepath.Join(dir, "src")
+	if err := os.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() *os.File {
+		in, err := os.Open(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { in.Close() })
+		return in
+	}
+
+	t.Run("file", func(t *testing.T) {
+		dst, err := os.Create(filepath.Join(dir, "dst"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer dst.Close()
+		// Дописывание после уже выведенного, как со вторым операндом
+		dst.Write([]byte("head\n"))
+
+		in := open()
+		if m := chooseMethods(int(dst.Fd()), int(in.Fd())); len(m) == 0 || m[0] != methodCopyFileRange {
+			t.Errorf("methods for file = %v, want copy_file_range first", m)
+		}
+		if err := CatFile(in, output{dst}); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := os.ReadFile(dst.Name())
+		if !bytes.Equal(got, append([]byte("head\n"), data...)) {
+			t.Errorf("file content differs: got %d bytes, want %d", len(got), len(data)+5)
+		}
+	})
+
+	t.Run("pipe", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		got := make(chan []byte)
+		go func() {
+			all, _ := io.ReadAll(r)
+			got <- all
+		}()
+
+		in := open()
+		if m := chooseMethods(int(w.Fd()), int(in.Fd())); len(m) != 1 || m[0] != methodSplice {
+			t.Errorf("methods for pipe = %v, want splice", m)
+		}
+		err = CatFile(in, output{w})
+		w.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if all := <-got; !bytes.Equal(all, data) {
+			t.Errorf("pipe content differs: got %d bytes, want %d", len(all), len(data))
+		}
+	})
+
+	t.Run("devnull", func(t *testing.T) {
+		null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer null.Close()
+		in := open()
+		if m := chooseMethods(int(null.Fd()), int(in.Fd())); len(m) != 0 {
+			t.Errorf("methods for /dev/null = %v, want buffer loop", m)
+		}
+		if err := CatFile(in, output{null}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "os"
+
+// fastCopy Вне Linux копирования средствами ядра нет, всегда обычный цикл.
+func fastCopy(dst, src *os.File) (handled bool, err error) {
+	return false, nil
+}
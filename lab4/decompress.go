@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Сигнатуры поддерживаемых форматов сжатия.
+var (
+	magicGzip  = []byte{0x1f, 0x8b}
+	magicBzip2 = []byte("BZh")                              // затем уровень '1'..'9'
+	magicBlock = []byte("1AY&SY")                           // сигнатура первого блока bzip2 (π)
+	magicEnd   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90} // конец потока (√π)
+	magicZstd  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Decompress Определяет формат src по первым байтам и возвращает поток
+// распакованных данных. Несжатые данные возвращаются как есть. Склеенные
+// архивы (cat a.gz b.gz > c.gz) читаются целиком. closeReader освобождает
+// ресурсы распаковщика; src он не закрывает.
+func Decompress(src io.Reader) (r io.Reader, closeReader func(), err error) {
+	br := bufio.NewReaderSize(src, copyBufferSize)
+	// Короткий файл — ошибка Peek, но это просто несжатые данные
+	head, _ := br.Peek(len(magicBzip2) + 1 + len(magicBlock))
+
+	switch {
+	case bytes.HasPrefix(head, magicGzip):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, func() { zr.Close() }, nil
+	case isBzip2(head):
+		return bzip2.NewReader(br), func() {}, nil
+	case bytes.HasPrefix(head, magicZstd):
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	}
+	return br, func() {}, nil
+}
+
+// isBzip2 Заголовок bzip2: "BZh", уровень сжатия и сигнатура блока. Одного
+// "BZh" мало — с этих букв может начинаться и обычный текст. Пустой архив
+// (без блоков) вместо сигнатуры блока содержит сигнатуру конца потока.
+func isBzip2(head []byte) bool {
+	if len(head) < len(magicBzip2)+1+len(magicBlock) || !bytes.HasPrefix(head, magicBzip2) {
+		return false
+	}
+	if level := head[len(magicBzip2)]; level < '1' || level > '9' {
+		return false
+	}
+	rest := head[len(magicBzip2)+1:]
+	return bytes.Equal(rest, magicBlock) || bytes.Equal(rest, magicEnd)
+}
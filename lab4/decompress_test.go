@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(data))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zstded(t *testing.T, data string) []byte {
+	t.Helper()
+	w, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	return w.EncodeAll([]byte(data), nil)
+}
+
+// bzipped В стандартной библиотеке нет кодировщика bzip2, поэтому данные
+// сжимает утилита bzip2 во время теста; без нее тест пропускается.
+func bzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	path, err := exec.LookPath("bzip2")
+	if err != nil {
+		t.Skip("bzip2 is not installed")
+	}
+	cmd := exec.Command(path, "-c")
+	cmd.Stdin = strings.NewReader(data)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func decompressAll(t *testing.T, input []byte) (string, error) {
+	t.Helper()
+	r, closeReader, err := Decompress(bytes.NewReader(input))
+	if err != nil {
+		return "", err
+	}
+	defer closeReader()
+	out, err := io.ReadAll(r)
+	return string(out), err
+}
+
+func TestDecompressRoundTrip(t *testing.T) {
+	text := strings.Repeat("log line with some text 0123456789\n", 5000)
+
+	tests := []struct {
+		name  string
+		input func(t *testing.T) []byte
+		want  string
+	}{
+		{"gzip", func(t *testing.T) []byte { return gzipped(t, text) }, text},
+		{"gzip concatenated", func(t *testing.T) []byte {
+			return append(gzipped(t, "first\n"), gzipped(t, "second\n")...)
+		}, "first\nsecond\n"},
+		{"bzip2", func(t *testing.T) []byte { return bzipped(t, text) }, text},
+		{"bzip2 empty", func(t *testing.T) []byte { return bzipped(t, "") }, ""},
+		{"zstd", func(t *testing.T) []byte { return zstded(t, text) }, text},
+		{"zstd concatenated", func(t *testing.T) []byte {
+			return append(zstded(t, "first\n"), zstded(t, "second\n")...)
+		}, "first\nsecond\n"},
+		{"plain", func(t *testing.T) []byte { return []byte(text) }, text},
+		{"plain empty", func(t *testing.T) []byte { return nil }, ""},
+		{"plain shorter than magic", func(t *testing.T) []byte { return []byte("x") }, "x"},
+		{"plain starting with BZh", func(t *testing.T) []byte { return []byte("BZh hello\n") }, "BZh hello\n"},
+		{"plain BZh with level", func(t *testing.T) []byte { return []byte("BZh9 is not a block\n") }, "BZh9 is not a block\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decompressAll(t, tt.input(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d bytes, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestDecompressCorrupt(t *testing.T) {
+	data := gzipped(t, strings.Repeat("abc", 1000))
+	if _, err := decompressAll(t, data[:len(data)/2]); err == nil {
+		t.Error("truncated gzip: no error")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"syscall"
+)
+
+// writeError Ошибка записи в вывод. Ее нужно отличать от ошибок чтения:
+// после нее продолжать со следующими файлами бессмысленно.
+type writeError struct {
+	err error
+}
+
+func (e *writeError) Error() string { return "write error: " + describe(e.err) }
+func (e *writeError) Unwrap() error { return e.err }
+
+// output Вывод, помечающий свои ошибки как writeError.
+type output struct {
+	w io.Writer
+}
+
+func (o output) Write(p []byte) (int, error) {
+	n, err := o.w.Write(p)
+	if err != nil {
+		err = &writeError{err}
+	}
+	return n, err
+}
+
+// describe Текст ошибки для сообщения «cat: ИМЯ: текст» без повторения имени
+// файла и операции, которые добавляет os.PathError.
+func describe(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "No such file or directory"
+	case errors.Is(err, fs.ErrPermission):
+		return "Permission denied"
+	case errors.Is(err, syscall.EISDIR):
+		return "Is a directory"
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
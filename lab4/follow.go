@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+// pollInterval Как часто проверять файл без уведомлений (и для страховки с ними).
+const pollInterval = time.Second
+
+// watcher Ожидание изменений файла: inotify в Linux или просто пауза.
+type watcher interface {
+	// Wait Возвращается, когда файл, возможно, изменился.
+	Wait() error
+	Close() error
+}
+
+// pollWatcher Запасной вариант: проверять файл раз в interval.
+type pollWatcher struct {
+	interval time.Duration
+}
+
+func (w pollWatcher) Wait() error { time.Sleep(w.interval); return nil }
+func (pollWatcher) Close() error  { return nil }
+
+// followOperand Режим -f: выводит файл, а затем продолжает выводить то, что в
+// него дописывают, как tail -f. Усеченный файл читается сначала, а замененный
+// (ротация: по имени теперь другой файл) — дочитывается и открывается заново.
+// Уведомления об этом идут в stderr, если не задан -s (quiet).
+// Возвращается при ошибке или после отмены ctx (тогда с nil).
+func followOperand(ctx context.Context, fname string, out io.Writer, flush func() error, stderr io.Writer, quiet bool) error {
+	f, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		f.Close()
+		return syscall.EISDIR
+	}
+
+	w, err := newWatcher(fname)
+	if err != nil {
+		w = pollWatcher{pollInterval}
+	}
+	defer w.Close()
+
+	fl := &follower{name: fname, out: out, flush: flush, stderr: stderr}
+	if quiet {
+		fl.stderr = nil
+	}
+	return fl.follow(ctx, f, w)
+}
+
+// follower Вывод и уведомления режима -f для одного файла.
+type follower struct {
+	name   string
+	out    io.Writer
+	flush  func() error
+	stderr io.Writer // nil — уведомления не выводятся (-s)
+}
+
+// follow Цикл -f по уже открытому файлу f; f закрывается при выходе.
+func (fl *follower) follow(ctx context.Context, f *os.File, w watcher) error {
+	defer func() { f.Close() }()
+
+	for {
+		// Тот же цикл, что и без -f: до конца файла
+		if err := CatFile(f, fl.out); err != nil {
+			return err
+		}
+		if err := fl.flush(); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := w.Wait(); err != nil {
+			return err
+		}
+
+		current, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		// По имени другой файл — старый дочитываем и переходим на новый.
+		// Если имени сейчас нет (между mv и созданием), ждем дальше на старом.
+		if named, err := os.Stat(fl.name); err == nil && !os.SameFile(named, current) {
+			if err := CatFile(f, fl.out); err != nil {
+				return err
+			}
+			next, err := os.Open(fl.name)
+			if err != nil {
+				continue
+			}
+			f.Close()
+			f = next
+			if err := fl.notice("cat: %s: file replaced, following new file\n", fl.name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pos, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if current.Size() < pos {
+			if err := fl.notice("cat: %s: file truncated\n", fl.name); err != nil {
+				return err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// notice Сообщение в stderr после сброса уже оформленного вывода, чтобы
+// порядок строк в stdout и stderr совпадал с порядком событий.
+func (fl *follower) notice(format string, args ...any) error {
+	if err := fl.flush(); err != nil {
+		return err
+	}
+	if fl.stderr != nil {
+		fmt.Fprintf(fl.stderr, format, args...)
+	}
+	return nil
+}
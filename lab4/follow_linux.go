@@ -0,0 +1,52 @@
+//go:build linux
+
+package main
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyWatcher Следит за каталогом файла: так видны и изменения самого
+// файла, и его замена при ротации, и не нужно переставлять наблюдение.
+// События других файлов каталога дают лишние проверки, но не ошибки.
+type inotifyWatcher struct {
+	fd  int
+	buf []byte
+}
+
+func newWatcher(fname string) (watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	mask := uint32(unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_CREATE | unix.IN_MOVED_TO |
+		unix.IN_MOVED_FROM | unix.IN_DELETE | unix.IN_CLOSE_WRITE)
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(fname), mask); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return &inotifyWatcher{fd: fd, buf: make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))}, nil
+}
+
+// Wait Ждет событий не дольше pollInterval (на случай ФС, где уведомлений нет,
+// например NFS) и вычитывает все накопившиеся события.
+func (w *inotifyWatcher) Wait() error {
+	fds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}}
+	if _, err := unix.Poll(fds, int(pollInterval.Milliseconds())); err != nil && err != unix.EINTR {
+		return err
+	}
+	for {
+		if _, err := unix.Read(w.fd, w.buf); err != nil {
+			if err == unix.EAGAIN || err == unix.EINTR {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (w *inotifyWatcher) Close() error {
+	return unix.Close(w.fd)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitOutput Ждет, пока файл path не станет равен want.
+func waitOutput(t *testing.T, path, want string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		got, _ := os.ReadFile(path)
+		if string(got) == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("output %q, want %q", got, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// appendFile Дописывает data в конец файла.
+func appendFile(path, data string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(data)
+	return err
+}
+
+// followSteps Дописывает в file, усекает его, затем переименовывает и создает
+// заново; после каждого шага ждет, пока out (общий для stdout и stderr) не
+// станет ожидаемым. line оформляет n-ю выведенную строку, notice — уведомление.
+func followSteps(t *testing.T, file, out string, line func(n int, s string) string, notice func(s string) string) {
+	t.Helper()
+	steps := []struct {
+		name string
+		do   func() error
+		want string
+	}{
+		{"initial", func() error { return nil }, line(1, "one")},
+		{"append", func() error { return appendFile(file, "two\n") }, line(2, "two")},
+		// Файл стал короче уже выведенного — читаем сначала
+		{"truncate", func() error { return os.WriteFile(file, []byte("3\n"), 0644) }, notice("file truncated") + line(3, "3")},
+		// Ротация: старый файл дочитывается, затем выводится новый
+		{"rotate", func() error {
+			if err := os.Rename(file, file+".1"); err != nil {
+				return err
+			}
+			if err := appendFile(file+".1", "four\n"); err != nil {
+				return err
+			}
+			return os.WriteFile(file, []byte("five\n"), 0644)
+		}, line(4, "four") + notice("file replaced, following new file") + line(5, "five")},
+		{"append to new file", func() error { return appendFile(file, "six\n") }, line(6, "six")},
+	}
+
+	want := ""
+	for _, step := range steps {
+		if err := step.do(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		want += step.want
+		waitOutput(t, out, want)
+	}
+}
+
+func TestRunFollow(t *testing.T) {
+	plain := func(n int, s string) string { return s + "\n" }
+	numbered := func(n int, s string) string { return fmt.Sprintf("%6d\t%s\n", n, s) }
+
+	tests := []struct {
+		name  string
+		flags []string
+		line  func(n int, s string) string
+		quiet bool
+	}{
+		{"plain", []string{"-f"}, plain, false},
+		// Нумерация идет через усечение и ротацию, уведомления не вклиниваются в строки
+		{"numbered", []string{"-n", "-f"}, numbered, false},
+		{"suppressed", []string{"-s", "-f"}, plain, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "log")
+			if err := os.WriteFile(file, []byte("one\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			// stdout и stderr — один файл, чтобы был виден порядок вывода
+			outPath := filepath.Join(dir, "out")
+			out, err := os.Create(outPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer out.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan int, 1)
+			go func() { done <- run(ctx, append(tt.flags, file), nil, out, out) }()
+
+			notice := func(s string) string { return "cat: " + file + ": " + s + "\n" }
+			if tt.quiet {
+				notice = func(string) string { return "" }
+			}
+			followSteps(t, file, outPath, tt.line, notice)
+
+			cancel()
+			select {
+			case status := <-done:
+				if status != 0 {
+					t.Errorf("status %d after cancel, want 0", status)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatal("run did not return after cancel")
+			}
+		})
+	}
+}
+
+// Тот же сценарий без inotify — опросом, как на ФС без уведомлений
+func TestFollowPolling(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "log")
+	if err := os.WriteFile(file, []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "out")
+	out, err := os.Create(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fl := &follower{name: file, out: output{out}, flush: func() error { return nil }, stderr: out}
+	done := make(chan error, 1)
+	go func() { done <- fl.follow(ctx, f, pollWatcher{10 * time.Millisecond}) }()
+
+	followSteps(t, file, outPath,
+		func(n int, s string) string { return s + "\n" },
+		func(s string) string { return "cat: " + file + ": " + s + "\n" })
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("follow returned %v after cancel, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("follow did not return after cancel")
+	}
+}
+
+// inotify будит ожидание сразу после записи, не дожидаясь pollInterval
+func TestInotifyWatcherWakesOnWrite(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "log")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := newWatcher(file)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer w.Close()
+
+	time.AfterFunc(50*time.Millisecond, func() { appendFile(file, "x") })
+	start := time.Now()
+	if err := w.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= pollInterval/2 {
+		t.Errorf("Wait took %v, want a wake-up on write", elapsed)
+	}
+}
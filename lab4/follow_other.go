@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// newWatcher Уведомлений вне Linux нет — followOperand опрашивает файл.
+func newWatcher(fname string) (watcher, error) {
+	return nil, errors.New("file notifications are not supported")
+}
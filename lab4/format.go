@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// FormatOptions Параметры оформления вывода (как у GNU cat).
+type FormatOptions struct {
+	Number         bool // -n: нумеровать все строки
+	NumberNonBlank bool // -b: нумеровать только непустые строки (важнее -n)
+	SqueezeBlank   bool // --squeeze-blank: несколько пустых строк подряд — одна
+	ShowEnds       bool // -E: '$' в конце каждой строки
+	ShowTabs       bool // -T: табуляция как ^I
+	ShowNonPrint   bool // -v: управляющие символы как ^X, байты >= 128 как M-X
+}
+
+// Any Задан ли хоть один параметр. Без них вывод идет как есть, без Formatter.
+func (o FormatOptions) Any() bool {
+	return o.Number || o.NumberNonBlank || o.SqueezeBlank || o.ShowEnds || o.ShowTabs || o.ShowNonPrint
+}
+
+// Formatter Оформляет поток по FormatOptions. Это io.Writer: CatFile пишет в
+// него как в обычный вывод, данные обрабатываются по мере поступления и в
+// память целиком не загружаются. Состояние (номер строки, пустые строки)
+// сохраняется между файлами, как в cat с несколькими операндами.
+type Formatter struct {
+	opts      FormatOptions
+	w         *bufio.Writer
+	line      int
+	lineStart bool
+	prevBlank bool
+}
+
+// NewFormatter Оборачивает dst. После каждого файла нужно вызывать Flush.
+func NewFormatter(dst io.Writer, opts FormatOptions) *Formatter {
+	return &Formatter{opts: opts, w: bufio.NewWriterSize(dst, 64*1024), lineStart: true}
+}
+
+// Write Оформляет p и пишет в буфер. Возвращает len(p), если не было ошибки вывода.
+func (f *Formatter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		if f.lineStart {
+			if c == '\n' {
+				if f.opts.SqueezeBlank && f.prevBlank {
+					continue
+				}
+				f.prevBlank = true
+				if f.opts.Number && !f.opts.NumberNonBlank {
+					f.number()
+				}
+			} else {
+				f.prevBlank = false
+				if f.opts.Number || f.opts.NumberNonBlank {
+					f.number()
+				}
+			}
+		}
+		f.lineStart = c == '\n'
+		f.char(c)
+	}
+	if err := f.err(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush Пишет накопленное в dst.
+func (f *Formatter) Flush() error {
+	return f.w.Flush()
+}
+
+func (f *Formatter) number() {
+	f.line++
+	fmt.Fprintf(f.w, "%6d\t", f.line)
+}
+
+// char Один байт с учетом -E, -T и -v.
+func (f *Formatter) char(c byte) {
+	switch {
+	case c == '\n':
+		if f.opts.ShowEnds {
+			f.w.WriteByte('$')
+		}
+		f.w.WriteByte('\n')
+	case c == '\t':
+		if f.opts.ShowTabs {
+			f.w.WriteString("^I")
+		} else {
+			f.w.WriteByte('\t')
+		}
+	case !f.opts.ShowNonPrint:
+		f.w.WriteByte(c)
+	default:
+		if c >= 128 {
+			f.w.WriteString("M-")
+			c -= 128
+		}
+		switch {
+		case c < 32:
+			f.w.WriteByte('^')
+			f.w.WriteByte(c + 64)
+		case c == 127:
+			f.w.WriteString("^?")
+		default:
+			f.w.WriteByte(c)
+		}
+	}
+}
+
+// err Ошибка вывода, если была. bufio.Writer запоминает ее и при Flush,
+// и при следующих записях, поэтому достаточно проверить ее пустым Write.
+func (f *Formatter) err() error {
+	_, err := f.w.Write(nil)
+	return err
+}
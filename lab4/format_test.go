@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// format Пропускает входы через один Formatter, как несколько файлов подряд
+// (с Flush после каждого).
+func format(t *testing.T, opts FormatOptions, inputs ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	f := NewFormatter(&buf, opts)
+	for _, in := range inputs {
+		if _, err := f.Write([]byte(in)); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.String()
+}
+
+func TestFormatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   FormatOptions
+		inputs []string
+		want   string
+	}{
+		{"plain", FormatOptions{}, []string{"a\tb\x01\n"}, "a\tb\x01\n"},
+		{"number", FormatOptions{Number: true}, []string{"a\n\nb"}, "     1\ta\n     2\t\n     3\tb"},
+		{"number nonblank", FormatOptions{NumberNonBlank: true}, []string{"a\n\nb\n"}, "     1\ta\n\n     2\tb\n"},
+		{"b wins over n", FormatOptions{Number: true, NumberNonBlank: true}, []string{"a\n\nb\n"}, "     1\ta\n\n     2\tb\n"},
+		{"squeeze", FormatOptions{SqueezeBlank: true}, []string{"a\n\n\n\nb\n\n"}, "a\n\nb\n\n"},
+		{"squeeze leading", FormatOptions{SqueezeBlank: true}, []string{"\n\n\na\n"}, "\na\n"},
+		{"squeeze numbers kept lines only", FormatOptions{SqueezeBlank: true, Number: true}, []string{"a\n\n\nb\n"}, "     1\ta\n     2\t\n     3\tb\n"},
+		{"show ends", FormatOptions{ShowEnds: true}, []string{"a\n\nb"}, "a$\n$\nb"},
+		{"show tabs", FormatOptions{ShowTabs: true}, []string{"a\tb\n"}, "a^Ib\n"},
+		{"nonprinting", FormatOptions{ShowNonPrint: true}, []string{"\x00\x01\x1b\x7f\x80\x9b\xe9\xff\t\n"}, "^@^A^[^?M-^@M-^[M-iM-^?\t\n"},
+		{"show all", FormatOptions{ShowNonPrint: true, ShowEnds: true, ShowTabs: true}, []string{"\t\x02\n"}, "^I^B$\n"},
+
+		// Состояние переходит через границу файлов
+		{"number across files", FormatOptions{Number: true}, []string{"a\n", "b\n"}, "     1\ta\n     2\tb\n"},
+		{"line continues into next file", FormatOptions{Number: true}, []string{"a", "b\nc\n"}, "     1\tab\n     2\tc\n"},
+		{"squeeze across files", FormatOptions{SqueezeBlank: true}, []string{"a\n\n", "\n\nb\n"}, "a\n\nb\n"},
+		{"nonblank across files", FormatOptions{NumberNonBlank: true}, []string{"a\n\n", "\nb\n"}, "     1\ta\n\n\n     2\tb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := format(t, tt.opts, tt.inputs...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Результат не зависит от того, какими кусками приходят данные.
+func TestFormatterChunking(t *testing.T) {
+	opts := FormatOptions{Number: true, SqueezeBlank: true, ShowEnds: true, ShowNonPrint: true}
+	input := "first\n\n\n\nsecond\t\x85\n\nthird"
+
+	whole := format(t, opts, input)
+	var bytewise []string
+	for i := range input {
+		bytewise = append(bytewise, input[i:i+1])
+	}
+	if got := format(t, opts, bytewise...); got != whole {
+		t.Errorf("byte by byte %q, whole %q", got, whole)
+	}
+}
@@ -1,3 +1,8 @@
 module lab4
 
 go 1.25
+
+require (
+	github.com/klauspost/compress v1.18.0
+	golang.org/x/sys v0.41.0
+)
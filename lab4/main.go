@@ -1,65 +1,198 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"syscall"
 )
 
-func CatFile(src io.Reader, dst io.Writer) error {
-	_, err := io.Copy(dst, src)
-	return err
+const usage = `Usage: cat [OPTION]... [FILE]...
+  -s                     не выводить сообщения об ошибках
+  -n, --number           нумеровать все строки
+  -b, --number-nonblank  нумеровать непустые строки (важнее -n)
+      --squeeze-blank    сжимать несколько пустых строк подряд в одну
+  -E, --show-ends        '$' в конце каждой строки
+  -T, --show-tabs        табуляция как ^I
+  -v, --show-nonprinting управляющие символы как ^X и M-X
+  -e                     то же, что -vE
+  -t                     то же, что -vT
+  -A, --show-all         то же, что -vET
+  -z, --auto-decompress  распаковывать файлы gzip, bzip2 и zstd
+  -f, --follow           после вывода последнего файла ждать и выводить
+                         дописанное в него (как tail -f), в том числе после
+                         усечения и ротации; файл читается как есть, без -z
+Без FILE или с FILE "-" читается стандартный ввод.
+`
+
+// Options Ключи командной строки.
+type Options struct {
+	Format         FormatOptions
+	SuppressErrors bool // -s
+	Decompress     bool // -z
+	Follow         bool // -f
+}
+
+// В GNU cat -s сжимает пустые строки, но здесь -s по заданию подавляет ошибки,
+// поэтому сжатие доступно только как --squeeze-blank.
+var longOptions = map[string]func(*Options){
+	"--number":           func(o *Options) { o.Format.Number = true },
+	"--number-nonblank":  func(o *Options) { o.Format.NumberNonBlank = true },
+	"--squeeze-blank":    func(o *Options) { o.Format.SqueezeBlank = true },
+	"--show-ends":        func(o *Options) { o.Format.ShowEnds = true },
+	"--show-tabs":        func(o *Options) { o.Format.ShowTabs = true },
+	"--show-nonprinting": func(o *Options) { o.Format.ShowNonPrint = true },
+	"--show-all":         func(o *Options) { o.Format.ShowNonPrint, o.Format.ShowEnds, o.Format.ShowTabs = true, true, true },
+	"--auto-decompress":  func(o *Options) { o.Decompress = true },
+	"--follow":           func(o *Options) { o.Follow = true },
+}
+
+// parseArgs Разбирает ключи (в том числе слитные, -nE) и имена файлов.
+// После "--" все аргументы считаются файлами.
+func parseArgs(args []string) (opts Options, files []string, err error) {
+	f := &opts.Format
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			files = append(files, args[i+1:]...)
+			return
+		case arg == "-" || !strings.HasPrefix(arg, "-"):
+			// Все, что не флаг - считаем файлами
+			files = append(files, arg)
+		case strings.HasPrefix(arg, "--"):
+			set, ok := longOptions[arg]
+			if !ok {
+				return opts, nil, fmt.Errorf("unknown option %s", arg)
+			}
+			set(&opts)
+		default:
+			for _, c := range arg[1:] {
+				switch c {
+				case 's':
+					opts.SuppressErrors = true
+				case 'z':
+					opts.Decompress = true
+				case 'f':
+					opts.Follow = true
+				case 'n':
+					f.Number = true
+				case 'b':
+					f.NumberNonBlank = true
+				case 'E':
+					f.ShowEnds = true
+				case 'T':
+					f.ShowTabs = true
+				case 'v':
+					f.ShowNonPrint = true
+				case 'e':
+					f.ShowNonPrint, f.ShowEnds = true, true
+				case 't':
+					f.ShowNonPrint, f.ShowTabs = true, true
+				case 'A':
+					f.ShowNonPrint, f.ShowEnds, f.ShowTabs = true, true, true
+				default:
+					return opts, nil, fmt.Errorf("unknown option -%c", c)
+				}
+			}
+		}
+	}
+	return
 }
 
 func main() {
-	args := os.Args[1:]
-	suppressErrors := false
-	var files []string
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run Вся работа cat; возвращает код возврата: 0 — все операнды выведены,
+// 1 — были ошибки (или неверные ключи). Отмена ctx завершает режим -f.
+func run(ctx context.Context, args []string, stdin, stdout *os.File, stderr io.Writer) int {
+	opts, files, err := parseArgs(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "cat: %v\n%s", err, usage)
+		return 1
+	}
+
+	// Без параметров оформления пишем прямо в Stdout
+	var out io.Writer = output{stdout}
+	flush := func() error { return nil }
+	if opts.Format.Any() {
+		formatter := NewFormatter(output{stdout}, opts.Format)
+		out = formatter
+		flush = formatter.Flush
+	}
 
-	for _, arg := range args {
-		if arg == "-s" {
-			suppressErrors = true
-			continue
+	// Код возврата 1, если хоть один операнд не удалось вывести.
+	// fail возвращает true, если продолжать бессмысленно (ошибка вывода).
+	status := 0
+	fail := func(name string, err error) bool {
+		status = 1
+		var werr *writeError
+		if errors.As(err, &werr) {
+			// Читатель канала закрылся (head, less) — выходим молча, как cat
+			if !isBrokenPipe(err) && !opts.SuppressErrors {
+				fmt.Fprintf(stderr, "cat: %v\n", err)
+			}
+			return true
+		}
+		if !opts.SuppressErrors {
+			fmt.Fprintf(stderr, "cat: %s: %s\n", name, describe(err))
 		}
-		// Все, что не флаг - считаем файлами
-		files = append(files, arg)
+		return false
 	}
 
 	// Если файлов нет — читаем Stdin
 	if len(files) == 0 {
-		if err := CatFile(os.Stdin, os.Stdout); err != nil {
-			// Ошибки вывода в Stderr
-			if !suppressErrors {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			}
-		}
-		return
+		files = []string{"-"}
 	}
 
 	// Читаем файлы по очереди
-	for _, fname := range files {
-		// Поддержка чтения из stdin через -
-		if fname == "-" {
-			err := CatFile(os.Stdin, os.Stdout)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	for i, fname := range files {
+		if opts.Follow && i == len(files)-1 && fname != "-" {
+			// Не возвращается, пока не случится ошибка или не отменят ctx
+			if err := followOperand(ctx, fname, out, flush, stderr, opts.SuppressErrors); err != nil {
+				fail(fname, err)
 			}
-			continue
+			break
 		}
+		if err := catOperand(fname, stdin, out, opts); err != nil && fail(fname, err) {
+			break
+		}
+		// Сбрасываем вывод до следующего файла, чтобы он не перемешался с сообщениями об ошибках
+		if err := flush(); err != nil && fail(fname, err) {
+			break
+		}
+	}
+	return status
+}
 
+// catOperand Выводит один операнд: файл или стандартный ввод ("-").
+func catOperand(fname string, stdin *os.File, out io.Writer, opts Options) error {
+	// Поддержка чтения из stdin через -
+	src := stdin
+	if fname != "-" {
 		f, err := os.Open(fname)
 		if err != nil {
-			if !suppressErrors {
-				fmt.Fprintf(os.Stderr, "cat: cannot open %s: %v\n", fname, err)
-			}
-			continue
+			return err
 		}
+		defer f.Close()
 
-		if err := CatFile(f, os.Stdout); err != nil {
-			if !suppressErrors {
-				fmt.Fprintf(os.Stderr, "cat: error reading %s: %v\n", fname, err)
-			}
+		// Каталог открывается без ошибки, но читать его нельзя
+		if info, err := f.Stat(); err == nil && info.IsDir() {
+			return syscall.EISDIR
 		}
-		f.Close()
+		src = f
+	}
+
+	if !opts.Decompress {
+		return CatFile(src, out)
+	}
+	r, closeReader, err := Decompress(src)
+	if err != nil {
+		return err
 	}
+	defer closeReader()
+	return CatFile(r, out)
 }
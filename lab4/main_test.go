@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    Options
+		files   []string
+		wantErr bool
+	}{
+		{args: nil},
+		{args: []string{"a", "b"}, files: []string{"a", "b"}},
+		{args: []string{"-nE", "f"}, want: Options{Format: FormatOptions{Number: true, ShowEnds: true}}, files: []string{"f"}},
+		{args: []string{"a", "-b", "b"}, want: Options{Format: FormatOptions{NumberNonBlank: true}}, files: []string{"a", "b"}},
+		{args: []string{"-s", "-zf"}, want: Options{SuppressErrors: true, Decompress: true, Follow: true}},
+		{args: []string{"-A"}, want: Options{Format: FormatOptions{ShowNonPrint: true, ShowEnds: true, ShowTabs: true}}},
+		{args: []string{"-e", "-t"}, want: Options{Format: FormatOptions{ShowNonPrint: true, ShowEnds: true, ShowTabs: true}}},
+		{args: []string{"--number", "--squeeze-blank", "--show-tabs"}, want: Options{Format: FormatOptions{Number: true, SqueezeBlank: true, ShowTabs: true}}},
+		{args: []string{"--auto-decompress", "--follow"}, want: Options{Decompress: true, Follow: true}},
+		{args: []string{"-"}, files: []string{"-"}},
+		{args: []string{"-n", "--", "-b", "-"}, want: Options{Format: FormatOptions{Number: true}}, files: []string{"-b", "-"}},
+		{args: []string{"-x"}, wantErr: true},
+		{args: []string{"-nx"}, wantErr: true},
+		{args: []string{"--bogus"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		opts, files, err := parseArgs(tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseArgs(%q): no error", tt.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseArgs(%q): %v", tt.args, err)
+			continue
+		}
+		if opts != tt.want || !reflect.DeepEqual(files, tt.files) {
+			t.Errorf("parseArgs(%q) = %+v, %q; want %+v, %q", tt.args, opts, files, tt.want, tt.files)
+		}
+	}
+}
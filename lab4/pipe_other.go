@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isBrokenPipe Запись в закрытый канал. Обычно процесс раньше убивает SIGPIPE,
+// но если сигнал игнорируется (унаследовано от родителя), write вернет EPIPE.
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
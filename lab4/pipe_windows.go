@@ -0,0 +1,16 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errorNoData ERROR_NO_DATA: запись в канал, читатель которого уже закрылся.
+const errorNoData syscall.Errno = 232
+
+// isBrokenPipe Запись в закрытый канал.
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, errorNoData) || errors.Is(err, syscall.ERROR_BROKEN_PIPE)
+}
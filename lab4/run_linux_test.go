@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&fs.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}, "No such file or directory"},
+		{&fs.PathError{Op: "open", Path: "x", Err: syscall.EACCES}, "Permission denied"},
+		{&fs.PathError{Op: "read", Path: "x", Err: syscall.EISDIR}, "Is a directory"},
+		{syscall.EISDIR, "Is a directory"},
+		{&fs.PathError{Op: "read", Path: "x", Err: syscall.EIO}, syscall.EIO.Error()},
+		{errors.New("gzip: invalid header"), "gzip: invalid header"},
+		{&writeError{syscall.ENOSPC}, "write error: " + syscall.ENOSPC.Error()},
+	}
+	for _, tt := range tests {
+		if got := describe(tt.err); got != tt.want {
+			t.Errorf("describe(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// catRun Запускает run с выводом в файл; возвращает код, вывод и stderr.
+func catRun(t *testing.T, stdin *os.File, args ...string) (int, string, string) {
+	t.Helper()
+	out, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	var stderr bytes.Buffer
+	status := run(context.Background(), args, stdin, out, &stderr)
+	data, _ := os.ReadFile(out.Name())
+	return status, string(data), stderr.String()
+}
+
+func TestRunExitStatus(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, []byte("data\n"), 0644)
+	missing := filepath.Join(dir, "missing")
+	locked := filepath.Join(dir, "locked")
+	os.WriteFile(locked, []byte("secret\n"), 0)
+
+	stdin, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+
+	tests := []struct {
+		name       string
+		args       []string
+		status     int
+		out        string
+		stderrHas  string
+		rootUnsafe bool // root читает файл и без прав
+	}{
+		{name: "ok", args: []string{file, file}, out: "data\ndata\n"},
+		{name: "stdin dash", args: []string{"-"}, out: "data\n"},
+		{name: "missing continues", args: []string{missing, file}, status: 1, out: "data\n", stderrHas: "missing: No such file or directory"},
+		{name: "directory", args: []string{dir}, status: 1, stderrHas: ": Is a directory"},
+		{name: "permission", args: []string{locked}, status: 1, stderrHas: "locked: Permission denied", rootUnsafe: true},
+		{name: "suppressed", args: []string{"-s", missing, dir, file}, status: 1, out: "data\n"},
+		{name: "bad option", args: []string{"-x", file}, status: 1, stderrHas: "unknown option -x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.rootUnsafe && os.Geteuid() == 0 {
+				t.Skip("root ignores file permissions")
+			}
+			stdin.Seek(0, 0)
+			status, out, stderr := catRun(t, stdin, tt.args...)
+			if status != tt.status || out != tt.out {
+				t.Errorf("status %d, output %q; want %d, %q", status, out, tt.status, tt.out)
+			}
+			if tt.stderrHas == "" && stderr != "" {
+				t.Errorf("unexpected stderr %q", stderr)
+			}
+			if !strings.Contains(stderr, tt.stderrHas) {
+				t.Errorf("stderr %q, want it to contain %q", stderr, tt.stderrHas)
+			}
+		})
+	}
+}
+
+// Ошибка записи прекращает работу: /dev/full — с сообщением, закрытый канал — молча.
+func TestRunWriteErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(file, bytes.Repeat([]byte("x"), 1<<20), 0644)
+
+	full, err := os.OpenFile("/dev/full", os.O_WRONLY, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer full.Close()
+	var stderr bytes.Buffer
+	if status := run(context.Background(), []string{"-n", file, file}, nil, full, &stderr); status != 1 {
+		t.Errorf("/dev/full: status %d, want 1", status)
+	}
+	if got := stderr.String(); strings.Count(got, "write error") != 1 {
+		t.Errorf("/dev/full: stderr %q, want exactly one write error", got)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	defer w.Close()
+	stderr.Reset()
+	if status := run(context.Background(), []string{file}, nil, w, &stderr); status != 1 {
+		t.Errorf("closed pipe: status %d, want 1", status)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("closed pipe: stderr %q, want nothing", stderr.String())
+	}
+}